@@ -0,0 +1,231 @@
+//go:build ignore
+
+// gen.go writes mock_gen.go, the Mock struct and its methods, from the
+// AccountsService interface in ../service.go.
+package main
+
+// stdlib
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+const (
+	source    = "../service.go"
+	output    = "mock_gen.go"
+	iface     = "AccountsService"
+	qualifier = "accountsservice"
+)
+
+type param struct {
+	name string
+	typ  string
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+	// iterator is the item type when the method returns only an iterator.
+	iterator string
+}
+
+func main() {
+	file, err := parser.ParseFile(token.NewFileSet(), source, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	methods := parse(file)
+	if methods == nil {
+		log.Fatalf("%s not found in %s", iface, source)
+	}
+
+	code, err := format.Source(generate(methods))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(output, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parse(file *ast.File) (methods []method) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != iface {
+			return true
+		}
+
+		for _, field := range spec.Type.(*ast.InterfaceType).Methods.List {
+			fn := field.Type.(*ast.FuncType)
+			m := method{name: field.Names[0].Name}
+
+			for _, p := range fn.Params.List {
+				for _, name := range p.Names {
+					m.params = append(m.params, param{name.Name, typeString(p.Type)})
+				}
+			}
+
+			for _, r := range fn.Results.List {
+				m.results = append(m.results, typeString(r.Type))
+			}
+
+			if star, ok := fn.Results.List[0].Type.(*ast.StarExpr); ok && len(m.results) == 1 {
+				if index, ok := star.X.(*ast.IndexExpr); ok {
+					m.iterator = typeString(index.Index)
+				}
+			}
+
+			methods = append(methods, m)
+		}
+
+		return false
+	})
+
+	return
+}
+
+// typeString prints a type expression, qualifying the identifiers declared by
+// the accountsservice package.
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return qualifier + "." + t.Name
+		}
+		return t.Name
+	case *ast.SelectorExpr:
+		return typeString(t.X.(*ast.Ident)) + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + typeString(t.Key) + "]" + typeString(t.Value)
+	case *ast.IndexExpr:
+		return typeString(t.X) + "[" + typeString(t.Index) + "]"
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+
+	log.Fatalf("unsupported type %T", expr)
+	return ""
+}
+
+func (m method) signature() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p.name + " " + p.typ
+	}
+	return "(" + strings.Join(params, ", ") + ") (" + strings.Join(m.results, ", ") + ")"
+}
+
+func (m method) funcType() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p.typ
+	}
+	return "func(" + strings.Join(params, ", ") + ") (" + strings.Join(m.results, ", ") + ")"
+}
+
+func (m method) args() []string {
+	args := make([]string, len(m.params))
+	for i, p := range m.params {
+		args[i] = p.name
+	}
+	return args
+}
+
+func generate(methods []method) []byte {
+	byName := map[string]method{}
+	for _, m := range methods {
+		byName[m.name] = m
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by gen.go from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package accountsservicemock\n\n")
+	fmt.Fprintf(&b, "// stdlib\nimport (\n\t\"context\"\n\t\"sync\"\n)\n\n")
+	fmt.Fprintf(&b, "// internal\nimport (\n\t\"github.com/the-control-group/go-accounts-service-client\"\n")
+	if usesCurrency(methods) {
+		fmt.Fprintf(&b, "\t\"github.com/the-control-group/go-currency\"\n")
+	}
+	fmt.Fprintf(&b, ")\n\n")
+	fmt.Fprintf(&b, "var _ %s.%s = (*Mock)(nil)\n\n", qualifier, iface)
+	fmt.Fprintf(&b, "// Mock implements %s.%s with a configurable func per method.\n", qualifier, iface)
+	fmt.Fprintf(&b, "type Mock struct {\n")
+
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\t%sFunc %s\n", m.name, m.funcType())
+	}
+
+	fmt.Fprintf(&b, "\n\tmu sync.Mutex\n\tcalls []Call\n}\n")
+
+	for _, m := range methods {
+		args := m.args()
+
+		fmt.Fprintf(&b, "\nfunc (m *Mock) %s%s {\n", m.name, m.signature())
+		fmt.Fprintf(&b, "\tm.record(%q", m.name)
+		for _, arg := range args {
+			fmt.Fprintf(&b, ", %s", arg)
+		}
+		fmt.Fprintf(&b, ")\n\n")
+
+		fmt.Fprintf(&b, "\tif m.%sFunc != nil {\n\t\treturn m.%sFunc(%s)\n\t}\n\n", m.name, m.name, strings.Join(args, ", "))
+
+		if _, ok := byName[m.name+"Context"]; ok {
+			fmt.Fprintf(&b, "\tif m.%sContextFunc != nil {\n\t\treturn m.%sContextFunc(%s)\n\t}\n\n",
+				m.name, m.name, strings.Join(append([]string{"context.Background()"}, args...), ", "))
+		}
+
+		if m.iterator != "" {
+			fmt.Fprintf(&b, "\treturn failing[%s](%s, notConfigured(%q))\n}\n", m.iterator, args[0], m.name)
+			continue
+		}
+
+		zeros := make([]string, len(m.results))
+		for i, r := range m.results {
+			zeros[i] = zero(r)
+		}
+		zeros[len(zeros)-1] = fmt.Sprintf("notConfigured(%q)", m.name)
+
+		fmt.Fprintf(&b, "\treturn %s\n}\n", strings.Join(zeros, ", "))
+	}
+
+	return b.Bytes()
+}
+
+func usesCurrency(methods []method) bool {
+	for _, m := range methods {
+		for _, p := range m.params {
+			if strings.Contains(p.typ, "currency.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func zero(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["), typ == "interface{}", typ == "error":
+		return "nil"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case typ == "int":
+		return "0"
+	}
+	return typ + "{}"
+}
@@ -0,0 +1,94 @@
+// Package accountsservicemock provides Mock, a configurable implementation of
+// accountsservice.AccountsService that records its calls.
+//
+//	mock := &accountsservicemock.Mock{
+//		GetCustomerFunc: func(customerId int) (*accountsservice.Customer, error) {
+//			return &accountsservice.Customer{Id: customerId}, nil
+//		},
+//	}
+//
+//	doSomething(mock)
+//
+//	calls := mock.CallsTo("GetCustomer")
+//
+// Each method calls its Func field. A method without a Context suffix falls
+// back to the Func of its Context variant, called with context.Background().
+// Methods that aren't configured return zero values and an error wrapping
+// ErrNotConfigured, or an iterator failing with it.
+//
+// The methods are generated from the interface; run go generate after
+// changing it.
+package accountsservicemock
+
+//go:generate go run gen.go
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+)
+
+// ErrNotConfigured is returned by methods whose Func isn't set.
+var ErrNotConfigured = errors.New("accountsservicemock: method not configured")
+
+// Call is a recorded method call.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{
+		Method: method,
+		Args:   args,
+	})
+}
+
+// Calls returns every call made so far, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to method, in order.
+func (m *Mock) CallsTo(method string) (calls []Call) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return
+}
+
+// Reset forgets the recorded calls. Configured funcs are kept.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+func notConfigured(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotConfigured, method)
+}
+
+// failing returns an iterator whose first page fails with err.
+func failing[T any](ctx context.Context, err error) *accountsservice.Iterator[T] {
+	return accountsservice.NewIterator(ctx, 0, func(ctx context.Context, limit, offset int) ([]T, error) {
+		return nil, err
+	})
+}
@@ -0,0 +1,881 @@
+// Code generated by gen.go from ../service.go; DO NOT EDIT.
+
+package accountsservicemock
+
+// stdlib
+import (
+	"context"
+	"sync"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+	"github.com/the-control-group/go-currency"
+)
+
+var _ accountsservice.AccountsService = (*Mock)(nil)
+
+// Mock implements accountsservice.AccountsService with a configurable func per method.
+type Mock struct {
+	GetCustomerFunc                       func(int) (*accountsservice.Customer, error)
+	GetCustomerContextFunc                func(context.Context, int) (*accountsservice.Customer, error)
+	GetCustomerOrdersFunc                 func(int) ([]accountsservice.Order, error)
+	GetCustomerOrdersContextFunc          func(context.Context, int) ([]accountsservice.Order, error)
+	GetCustomerPaymentOptionsFunc         func(int) ([]accountsservice.PaymentOption, error)
+	GetCustomerPaymentOptionsContextFunc  func(context.Context, int) ([]accountsservice.PaymentOption, error)
+	GetCustomerTransactionsFunc           func(int) ([]accountsservice.Transaction, error)
+	GetCustomerTransactionsContextFunc    func(context.Context, int) ([]accountsservice.Transaction, error)
+	CreateCustomerFunc                    func(*accountsservice.Customer) (*accountsservice.Customer, error)
+	CreateCustomerContextFunc             func(context.Context, *accountsservice.Customer) (*accountsservice.Customer, error)
+	UpdateCustomerFunc                    func(int, *accountsservice.CustomerUpdate) (*accountsservice.Customer, error)
+	UpdateCustomerContextFunc             func(context.Context, int, *accountsservice.CustomerUpdate) (*accountsservice.Customer, error)
+	GetSubscriptionFunc                   func(int) (*accountsservice.Subscription, error)
+	GetSubscriptionContextFunc            func(context.Context, int) (*accountsservice.Subscription, error)
+	GetSubscriptionByOrderPlanFunc        func(int, string) (*accountsservice.Subscription, error)
+	GetSubscriptionByOrderPlanContextFunc func(context.Context, int, string) (*accountsservice.Subscription, error)
+	GetSubscriptionOrdersFunc             func(int) ([]accountsservice.Order, error)
+	GetSubscriptionOrdersContextFunc      func(context.Context, int) ([]accountsservice.Order, error)
+	CancelSubscriptionFunc                func(int, bool, string) (*accountsservice.Subscription, error)
+	CancelSubscriptionContextFunc         func(context.Context, int, bool, string) (*accountsservice.Subscription, error)
+	PauseSubscriptionFunc                 func(int) (*accountsservice.Subscription, error)
+	PauseSubscriptionContextFunc          func(context.Context, int) (*accountsservice.Subscription, error)
+	ResumeSubscriptionFunc                func(int) (*accountsservice.Subscription, error)
+	ResumeSubscriptionContextFunc         func(context.Context, int) (*accountsservice.Subscription, error)
+	ChangeSubscriptionPlanFunc            func(int, string, bool) (*accountsservice.Subscription, error)
+	ChangeSubscriptionPlanContextFunc     func(context.Context, int, string, bool) (*accountsservice.Subscription, error)
+	GetPlanFunc                           func(string, string) (*accountsservice.Plan, error)
+	GetPlanContextFunc                    func(context.Context, string, string) (*accountsservice.Plan, error)
+	GetProductFunc                        func(string, string) (*accountsservice.Product, error)
+	GetProductContextFunc                 func(context.Context, string, string) (*accountsservice.Product, error)
+	GetOrderFunc                          func(int) (*accountsservice.Order, error)
+	GetOrderContextFunc                   func(context.Context, int) (*accountsservice.Order, error)
+	GetOrderSubscriptionsFunc             func(int) ([]accountsservice.Subscription, error)
+	GetOrderSubscriptionsContextFunc      func(context.Context, int) ([]accountsservice.Subscription, error)
+	GetOrderPlansFunc                     func(int) ([]accountsservice.OrderPlan, error)
+	GetOrderPlansContextFunc              func(context.Context, int) ([]accountsservice.OrderPlan, error)
+	GetOrderProductsFunc                  func(int) ([]accountsservice.OrderProduct, error)
+	GetOrderProductsContextFunc           func(context.Context, int) ([]accountsservice.OrderProduct, error)
+	GetOrdersAggregateFunc                func(*accountsservice.Filter, string, []string) (map[string]interface{}, error)
+	GetOrdersAggregateContextFunc         func(context.Context, *accountsservice.Filter, string, []string) (map[string]interface{}, error)
+	CreateOrderFunc                       func(*accountsservice.NewOrder) (*accountsservice.Checkout, error)
+	CreateOrderContextFunc                func(context.Context, *accountsservice.NewOrder) (*accountsservice.Checkout, error)
+	GetPaymentOptionFunc                  func(int) (*accountsservice.PaymentOption, error)
+	GetPaymentOptionContextFunc           func(context.Context, int) (*accountsservice.PaymentOption, error)
+	GetPaymentOptionsFunc                 func(*accountsservice.Filter) ([]accountsservice.PaymentOption, error)
+	GetPaymentOptionsContextFunc          func(context.Context, *accountsservice.Filter) ([]accountsservice.PaymentOption, error)
+	CreatePaymentOptionFunc               func(*accountsservice.NewPaymentOption) (*accountsservice.PaymentOption, error)
+	CreatePaymentOptionContextFunc        func(context.Context, *accountsservice.NewPaymentOption) (*accountsservice.PaymentOption, error)
+	UpdatePaymentOptionExpiryFunc         func(int, string, string) (*accountsservice.PaymentOption, error)
+	UpdatePaymentOptionExpiryContextFunc  func(context.Context, int, string, string) (*accountsservice.PaymentOption, error)
+	SetDefaultPaymentOptionFunc           func(int) (*accountsservice.PaymentOption, error)
+	SetDefaultPaymentOptionContextFunc    func(context.Context, int) (*accountsservice.PaymentOption, error)
+	DeactivatePaymentOptionFunc           func(int) (*accountsservice.PaymentOption, error)
+	DeactivatePaymentOptionContextFunc    func(context.Context, int) (*accountsservice.PaymentOption, error)
+	GetTransactionsFunc                   func(*accountsservice.Filter) ([]accountsservice.Transaction, error)
+	GetTransactionsContextFunc            func(context.Context, *accountsservice.Filter) ([]accountsservice.Transaction, error)
+	RefundTransactionFunc                 func(*accountsservice.Transaction, *currency.Amount, string) (*accountsservice.Transaction, error)
+	RefundTransactionContextFunc          func(context.Context, *accountsservice.Transaction, *currency.Amount, string) (*accountsservice.Transaction, error)
+	VoidTransactionFunc                   func(int, string) (*accountsservice.Transaction, error)
+	VoidTransactionContextFunc            func(context.Context, int, string) (*accountsservice.Transaction, error)
+	ListCustomerOrdersFunc                func(context.Context, int, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Order]
+	ListCustomerPaymentOptionsFunc        func(context.Context, int, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.PaymentOption]
+	ListCustomerTransactionsFunc          func(context.Context, int, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Transaction]
+	ListOrderSubscriptionsFunc            func(context.Context, int, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Subscription]
+	ListSubscriptionOrdersFunc            func(context.Context, int, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Order]
+	ListPaymentOptionsFunc                func(context.Context, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.PaymentOption]
+	ListTransactionsFunc                  func(context.Context, *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Transaction]
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (m *Mock) GetCustomer(customerId int) (*accountsservice.Customer, error) {
+	m.record("GetCustomer", customerId)
+
+	if m.GetCustomerFunc != nil {
+		return m.GetCustomerFunc(customerId)
+	}
+
+	if m.GetCustomerContextFunc != nil {
+		return m.GetCustomerContextFunc(context.Background(), customerId)
+	}
+
+	return nil, notConfigured("GetCustomer")
+}
+
+func (m *Mock) GetCustomerContext(ctx context.Context, customerId int) (*accountsservice.Customer, error) {
+	m.record("GetCustomerContext", ctx, customerId)
+
+	if m.GetCustomerContextFunc != nil {
+		return m.GetCustomerContextFunc(ctx, customerId)
+	}
+
+	return nil, notConfigured("GetCustomerContext")
+}
+
+func (m *Mock) GetCustomerOrders(customerId int) ([]accountsservice.Order, error) {
+	m.record("GetCustomerOrders", customerId)
+
+	if m.GetCustomerOrdersFunc != nil {
+		return m.GetCustomerOrdersFunc(customerId)
+	}
+
+	if m.GetCustomerOrdersContextFunc != nil {
+		return m.GetCustomerOrdersContextFunc(context.Background(), customerId)
+	}
+
+	return nil, notConfigured("GetCustomerOrders")
+}
+
+func (m *Mock) GetCustomerOrdersContext(ctx context.Context, customerId int) ([]accountsservice.Order, error) {
+	m.record("GetCustomerOrdersContext", ctx, customerId)
+
+	if m.GetCustomerOrdersContextFunc != nil {
+		return m.GetCustomerOrdersContextFunc(ctx, customerId)
+	}
+
+	return nil, notConfigured("GetCustomerOrdersContext")
+}
+
+func (m *Mock) GetCustomerPaymentOptions(customerId int) ([]accountsservice.PaymentOption, error) {
+	m.record("GetCustomerPaymentOptions", customerId)
+
+	if m.GetCustomerPaymentOptionsFunc != nil {
+		return m.GetCustomerPaymentOptionsFunc(customerId)
+	}
+
+	if m.GetCustomerPaymentOptionsContextFunc != nil {
+		return m.GetCustomerPaymentOptionsContextFunc(context.Background(), customerId)
+	}
+
+	return nil, notConfigured("GetCustomerPaymentOptions")
+}
+
+func (m *Mock) GetCustomerPaymentOptionsContext(ctx context.Context, customerId int) ([]accountsservice.PaymentOption, error) {
+	m.record("GetCustomerPaymentOptionsContext", ctx, customerId)
+
+	if m.GetCustomerPaymentOptionsContextFunc != nil {
+		return m.GetCustomerPaymentOptionsContextFunc(ctx, customerId)
+	}
+
+	return nil, notConfigured("GetCustomerPaymentOptionsContext")
+}
+
+func (m *Mock) GetCustomerTransactions(customerId int) ([]accountsservice.Transaction, error) {
+	m.record("GetCustomerTransactions", customerId)
+
+	if m.GetCustomerTransactionsFunc != nil {
+		return m.GetCustomerTransactionsFunc(customerId)
+	}
+
+	if m.GetCustomerTransactionsContextFunc != nil {
+		return m.GetCustomerTransactionsContextFunc(context.Background(), customerId)
+	}
+
+	return nil, notConfigured("GetCustomerTransactions")
+}
+
+func (m *Mock) GetCustomerTransactionsContext(ctx context.Context, customerId int) ([]accountsservice.Transaction, error) {
+	m.record("GetCustomerTransactionsContext", ctx, customerId)
+
+	if m.GetCustomerTransactionsContextFunc != nil {
+		return m.GetCustomerTransactionsContextFunc(ctx, customerId)
+	}
+
+	return nil, notConfigured("GetCustomerTransactionsContext")
+}
+
+func (m *Mock) CreateCustomer(customer *accountsservice.Customer) (*accountsservice.Customer, error) {
+	m.record("CreateCustomer", customer)
+
+	if m.CreateCustomerFunc != nil {
+		return m.CreateCustomerFunc(customer)
+	}
+
+	if m.CreateCustomerContextFunc != nil {
+		return m.CreateCustomerContextFunc(context.Background(), customer)
+	}
+
+	return nil, notConfigured("CreateCustomer")
+}
+
+func (m *Mock) CreateCustomerContext(ctx context.Context, customer *accountsservice.Customer) (*accountsservice.Customer, error) {
+	m.record("CreateCustomerContext", ctx, customer)
+
+	if m.CreateCustomerContextFunc != nil {
+		return m.CreateCustomerContextFunc(ctx, customer)
+	}
+
+	return nil, notConfigured("CreateCustomerContext")
+}
+
+func (m *Mock) UpdateCustomer(customerId int, update *accountsservice.CustomerUpdate) (*accountsservice.Customer, error) {
+	m.record("UpdateCustomer", customerId, update)
+
+	if m.UpdateCustomerFunc != nil {
+		return m.UpdateCustomerFunc(customerId, update)
+	}
+
+	if m.UpdateCustomerContextFunc != nil {
+		return m.UpdateCustomerContextFunc(context.Background(), customerId, update)
+	}
+
+	return nil, notConfigured("UpdateCustomer")
+}
+
+func (m *Mock) UpdateCustomerContext(ctx context.Context, customerId int, update *accountsservice.CustomerUpdate) (*accountsservice.Customer, error) {
+	m.record("UpdateCustomerContext", ctx, customerId, update)
+
+	if m.UpdateCustomerContextFunc != nil {
+		return m.UpdateCustomerContextFunc(ctx, customerId, update)
+	}
+
+	return nil, notConfigured("UpdateCustomerContext")
+}
+
+func (m *Mock) GetSubscription(subscriptionId int) (*accountsservice.Subscription, error) {
+	m.record("GetSubscription", subscriptionId)
+
+	if m.GetSubscriptionFunc != nil {
+		return m.GetSubscriptionFunc(subscriptionId)
+	}
+
+	if m.GetSubscriptionContextFunc != nil {
+		return m.GetSubscriptionContextFunc(context.Background(), subscriptionId)
+	}
+
+	return nil, notConfigured("GetSubscription")
+}
+
+func (m *Mock) GetSubscriptionContext(ctx context.Context, subscriptionId int) (*accountsservice.Subscription, error) {
+	m.record("GetSubscriptionContext", ctx, subscriptionId)
+
+	if m.GetSubscriptionContextFunc != nil {
+		return m.GetSubscriptionContextFunc(ctx, subscriptionId)
+	}
+
+	return nil, notConfigured("GetSubscriptionContext")
+}
+
+func (m *Mock) GetSubscriptionByOrderPlan(orderId int, planSku string) (*accountsservice.Subscription, error) {
+	m.record("GetSubscriptionByOrderPlan", orderId, planSku)
+
+	if m.GetSubscriptionByOrderPlanFunc != nil {
+		return m.GetSubscriptionByOrderPlanFunc(orderId, planSku)
+	}
+
+	if m.GetSubscriptionByOrderPlanContextFunc != nil {
+		return m.GetSubscriptionByOrderPlanContextFunc(context.Background(), orderId, planSku)
+	}
+
+	return nil, notConfigured("GetSubscriptionByOrderPlan")
+}
+
+func (m *Mock) GetSubscriptionByOrderPlanContext(ctx context.Context, orderId int, planSku string) (*accountsservice.Subscription, error) {
+	m.record("GetSubscriptionByOrderPlanContext", ctx, orderId, planSku)
+
+	if m.GetSubscriptionByOrderPlanContextFunc != nil {
+		return m.GetSubscriptionByOrderPlanContextFunc(ctx, orderId, planSku)
+	}
+
+	return nil, notConfigured("GetSubscriptionByOrderPlanContext")
+}
+
+func (m *Mock) GetSubscriptionOrders(subscriptionId int) ([]accountsservice.Order, error) {
+	m.record("GetSubscriptionOrders", subscriptionId)
+
+	if m.GetSubscriptionOrdersFunc != nil {
+		return m.GetSubscriptionOrdersFunc(subscriptionId)
+	}
+
+	if m.GetSubscriptionOrdersContextFunc != nil {
+		return m.GetSubscriptionOrdersContextFunc(context.Background(), subscriptionId)
+	}
+
+	return nil, notConfigured("GetSubscriptionOrders")
+}
+
+func (m *Mock) GetSubscriptionOrdersContext(ctx context.Context, subscriptionId int) ([]accountsservice.Order, error) {
+	m.record("GetSubscriptionOrdersContext", ctx, subscriptionId)
+
+	if m.GetSubscriptionOrdersContextFunc != nil {
+		return m.GetSubscriptionOrdersContextFunc(ctx, subscriptionId)
+	}
+
+	return nil, notConfigured("GetSubscriptionOrdersContext")
+}
+
+func (m *Mock) CancelSubscription(subscriptionId int, atPeriodEnd bool, reason string) (*accountsservice.Subscription, error) {
+	m.record("CancelSubscription", subscriptionId, atPeriodEnd, reason)
+
+	if m.CancelSubscriptionFunc != nil {
+		return m.CancelSubscriptionFunc(subscriptionId, atPeriodEnd, reason)
+	}
+
+	if m.CancelSubscriptionContextFunc != nil {
+		return m.CancelSubscriptionContextFunc(context.Background(), subscriptionId, atPeriodEnd, reason)
+	}
+
+	return nil, notConfigured("CancelSubscription")
+}
+
+func (m *Mock) CancelSubscriptionContext(ctx context.Context, subscriptionId int, atPeriodEnd bool, reason string) (*accountsservice.Subscription, error) {
+	m.record("CancelSubscriptionContext", ctx, subscriptionId, atPeriodEnd, reason)
+
+	if m.CancelSubscriptionContextFunc != nil {
+		return m.CancelSubscriptionContextFunc(ctx, subscriptionId, atPeriodEnd, reason)
+	}
+
+	return nil, notConfigured("CancelSubscriptionContext")
+}
+
+func (m *Mock) PauseSubscription(subscriptionId int) (*accountsservice.Subscription, error) {
+	m.record("PauseSubscription", subscriptionId)
+
+	if m.PauseSubscriptionFunc != nil {
+		return m.PauseSubscriptionFunc(subscriptionId)
+	}
+
+	if m.PauseSubscriptionContextFunc != nil {
+		return m.PauseSubscriptionContextFunc(context.Background(), subscriptionId)
+	}
+
+	return nil, notConfigured("PauseSubscription")
+}
+
+func (m *Mock) PauseSubscriptionContext(ctx context.Context, subscriptionId int) (*accountsservice.Subscription, error) {
+	m.record("PauseSubscriptionContext", ctx, subscriptionId)
+
+	if m.PauseSubscriptionContextFunc != nil {
+		return m.PauseSubscriptionContextFunc(ctx, subscriptionId)
+	}
+
+	return nil, notConfigured("PauseSubscriptionContext")
+}
+
+func (m *Mock) ResumeSubscription(subscriptionId int) (*accountsservice.Subscription, error) {
+	m.record("ResumeSubscription", subscriptionId)
+
+	if m.ResumeSubscriptionFunc != nil {
+		return m.ResumeSubscriptionFunc(subscriptionId)
+	}
+
+	if m.ResumeSubscriptionContextFunc != nil {
+		return m.ResumeSubscriptionContextFunc(context.Background(), subscriptionId)
+	}
+
+	return nil, notConfigured("ResumeSubscription")
+}
+
+func (m *Mock) ResumeSubscriptionContext(ctx context.Context, subscriptionId int) (*accountsservice.Subscription, error) {
+	m.record("ResumeSubscriptionContext", ctx, subscriptionId)
+
+	if m.ResumeSubscriptionContextFunc != nil {
+		return m.ResumeSubscriptionContextFunc(ctx, subscriptionId)
+	}
+
+	return nil, notConfigured("ResumeSubscriptionContext")
+}
+
+func (m *Mock) ChangeSubscriptionPlan(subscriptionId int, planSku string, prorate bool) (*accountsservice.Subscription, error) {
+	m.record("ChangeSubscriptionPlan", subscriptionId, planSku, prorate)
+
+	if m.ChangeSubscriptionPlanFunc != nil {
+		return m.ChangeSubscriptionPlanFunc(subscriptionId, planSku, prorate)
+	}
+
+	if m.ChangeSubscriptionPlanContextFunc != nil {
+		return m.ChangeSubscriptionPlanContextFunc(context.Background(), subscriptionId, planSku, prorate)
+	}
+
+	return nil, notConfigured("ChangeSubscriptionPlan")
+}
+
+func (m *Mock) ChangeSubscriptionPlanContext(ctx context.Context, subscriptionId int, planSku string, prorate bool) (*accountsservice.Subscription, error) {
+	m.record("ChangeSubscriptionPlanContext", ctx, subscriptionId, planSku, prorate)
+
+	if m.ChangeSubscriptionPlanContextFunc != nil {
+		return m.ChangeSubscriptionPlanContextFunc(ctx, subscriptionId, planSku, prorate)
+	}
+
+	return nil, notConfigured("ChangeSubscriptionPlanContext")
+}
+
+func (m *Mock) GetPlan(brandSlug string, sku string) (*accountsservice.Plan, error) {
+	m.record("GetPlan", brandSlug, sku)
+
+	if m.GetPlanFunc != nil {
+		return m.GetPlanFunc(brandSlug, sku)
+	}
+
+	if m.GetPlanContextFunc != nil {
+		return m.GetPlanContextFunc(context.Background(), brandSlug, sku)
+	}
+
+	return nil, notConfigured("GetPlan")
+}
+
+func (m *Mock) GetPlanContext(ctx context.Context, brandSlug string, sku string) (*accountsservice.Plan, error) {
+	m.record("GetPlanContext", ctx, brandSlug, sku)
+
+	if m.GetPlanContextFunc != nil {
+		return m.GetPlanContextFunc(ctx, brandSlug, sku)
+	}
+
+	return nil, notConfigured("GetPlanContext")
+}
+
+func (m *Mock) GetProduct(brandSlug string, sku string) (*accountsservice.Product, error) {
+	m.record("GetProduct", brandSlug, sku)
+
+	if m.GetProductFunc != nil {
+		return m.GetProductFunc(brandSlug, sku)
+	}
+
+	if m.GetProductContextFunc != nil {
+		return m.GetProductContextFunc(context.Background(), brandSlug, sku)
+	}
+
+	return nil, notConfigured("GetProduct")
+}
+
+func (m *Mock) GetProductContext(ctx context.Context, brandSlug string, sku string) (*accountsservice.Product, error) {
+	m.record("GetProductContext", ctx, brandSlug, sku)
+
+	if m.GetProductContextFunc != nil {
+		return m.GetProductContextFunc(ctx, brandSlug, sku)
+	}
+
+	return nil, notConfigured("GetProductContext")
+}
+
+func (m *Mock) GetOrder(orderId int) (*accountsservice.Order, error) {
+	m.record("GetOrder", orderId)
+
+	if m.GetOrderFunc != nil {
+		return m.GetOrderFunc(orderId)
+	}
+
+	if m.GetOrderContextFunc != nil {
+		return m.GetOrderContextFunc(context.Background(), orderId)
+	}
+
+	return nil, notConfigured("GetOrder")
+}
+
+func (m *Mock) GetOrderContext(ctx context.Context, orderId int) (*accountsservice.Order, error) {
+	m.record("GetOrderContext", ctx, orderId)
+
+	if m.GetOrderContextFunc != nil {
+		return m.GetOrderContextFunc(ctx, orderId)
+	}
+
+	return nil, notConfigured("GetOrderContext")
+}
+
+func (m *Mock) GetOrderSubscriptions(orderId int) ([]accountsservice.Subscription, error) {
+	m.record("GetOrderSubscriptions", orderId)
+
+	if m.GetOrderSubscriptionsFunc != nil {
+		return m.GetOrderSubscriptionsFunc(orderId)
+	}
+
+	if m.GetOrderSubscriptionsContextFunc != nil {
+		return m.GetOrderSubscriptionsContextFunc(context.Background(), orderId)
+	}
+
+	return nil, notConfigured("GetOrderSubscriptions")
+}
+
+func (m *Mock) GetOrderSubscriptionsContext(ctx context.Context, orderId int) ([]accountsservice.Subscription, error) {
+	m.record("GetOrderSubscriptionsContext", ctx, orderId)
+
+	if m.GetOrderSubscriptionsContextFunc != nil {
+		return m.GetOrderSubscriptionsContextFunc(ctx, orderId)
+	}
+
+	return nil, notConfigured("GetOrderSubscriptionsContext")
+}
+
+func (m *Mock) GetOrderPlans(orderId int) ([]accountsservice.OrderPlan, error) {
+	m.record("GetOrderPlans", orderId)
+
+	if m.GetOrderPlansFunc != nil {
+		return m.GetOrderPlansFunc(orderId)
+	}
+
+	if m.GetOrderPlansContextFunc != nil {
+		return m.GetOrderPlansContextFunc(context.Background(), orderId)
+	}
+
+	return nil, notConfigured("GetOrderPlans")
+}
+
+func (m *Mock) GetOrderPlansContext(ctx context.Context, orderId int) ([]accountsservice.OrderPlan, error) {
+	m.record("GetOrderPlansContext", ctx, orderId)
+
+	if m.GetOrderPlansContextFunc != nil {
+		return m.GetOrderPlansContextFunc(ctx, orderId)
+	}
+
+	return nil, notConfigured("GetOrderPlansContext")
+}
+
+func (m *Mock) GetOrderProducts(orderId int) ([]accountsservice.OrderProduct, error) {
+	m.record("GetOrderProducts", orderId)
+
+	if m.GetOrderProductsFunc != nil {
+		return m.GetOrderProductsFunc(orderId)
+	}
+
+	if m.GetOrderProductsContextFunc != nil {
+		return m.GetOrderProductsContextFunc(context.Background(), orderId)
+	}
+
+	return nil, notConfigured("GetOrderProducts")
+}
+
+func (m *Mock) GetOrderProductsContext(ctx context.Context, orderId int) ([]accountsservice.OrderProduct, error) {
+	m.record("GetOrderProductsContext", ctx, orderId)
+
+	if m.GetOrderProductsContextFunc != nil {
+		return m.GetOrderProductsContextFunc(ctx, orderId)
+	}
+
+	return nil, notConfigured("GetOrderProductsContext")
+}
+
+func (m *Mock) GetOrdersAggregate(filter *accountsservice.Filter, group string, aggregate []string) (map[string]interface{}, error) {
+	m.record("GetOrdersAggregate", filter, group, aggregate)
+
+	if m.GetOrdersAggregateFunc != nil {
+		return m.GetOrdersAggregateFunc(filter, group, aggregate)
+	}
+
+	if m.GetOrdersAggregateContextFunc != nil {
+		return m.GetOrdersAggregateContextFunc(context.Background(), filter, group, aggregate)
+	}
+
+	return nil, notConfigured("GetOrdersAggregate")
+}
+
+func (m *Mock) GetOrdersAggregateContext(ctx context.Context, filter *accountsservice.Filter, group string, aggregate []string) (map[string]interface{}, error) {
+	m.record("GetOrdersAggregateContext", ctx, filter, group, aggregate)
+
+	if m.GetOrdersAggregateContextFunc != nil {
+		return m.GetOrdersAggregateContextFunc(ctx, filter, group, aggregate)
+	}
+
+	return nil, notConfigured("GetOrdersAggregateContext")
+}
+
+func (m *Mock) CreateOrder(order *accountsservice.NewOrder) (*accountsservice.Checkout, error) {
+	m.record("CreateOrder", order)
+
+	if m.CreateOrderFunc != nil {
+		return m.CreateOrderFunc(order)
+	}
+
+	if m.CreateOrderContextFunc != nil {
+		return m.CreateOrderContextFunc(context.Background(), order)
+	}
+
+	return nil, notConfigured("CreateOrder")
+}
+
+func (m *Mock) CreateOrderContext(ctx context.Context, order *accountsservice.NewOrder) (*accountsservice.Checkout, error) {
+	m.record("CreateOrderContext", ctx, order)
+
+	if m.CreateOrderContextFunc != nil {
+		return m.CreateOrderContextFunc(ctx, order)
+	}
+
+	return nil, notConfigured("CreateOrderContext")
+}
+
+func (m *Mock) GetPaymentOption(paymentOptionId int) (*accountsservice.PaymentOption, error) {
+	m.record("GetPaymentOption", paymentOptionId)
+
+	if m.GetPaymentOptionFunc != nil {
+		return m.GetPaymentOptionFunc(paymentOptionId)
+	}
+
+	if m.GetPaymentOptionContextFunc != nil {
+		return m.GetPaymentOptionContextFunc(context.Background(), paymentOptionId)
+	}
+
+	return nil, notConfigured("GetPaymentOption")
+}
+
+func (m *Mock) GetPaymentOptionContext(ctx context.Context, paymentOptionId int) (*accountsservice.PaymentOption, error) {
+	m.record("GetPaymentOptionContext", ctx, paymentOptionId)
+
+	if m.GetPaymentOptionContextFunc != nil {
+		return m.GetPaymentOptionContextFunc(ctx, paymentOptionId)
+	}
+
+	return nil, notConfigured("GetPaymentOptionContext")
+}
+
+func (m *Mock) GetPaymentOptions(filter *accountsservice.Filter) ([]accountsservice.PaymentOption, error) {
+	m.record("GetPaymentOptions", filter)
+
+	if m.GetPaymentOptionsFunc != nil {
+		return m.GetPaymentOptionsFunc(filter)
+	}
+
+	if m.GetPaymentOptionsContextFunc != nil {
+		return m.GetPaymentOptionsContextFunc(context.Background(), filter)
+	}
+
+	return nil, notConfigured("GetPaymentOptions")
+}
+
+func (m *Mock) GetPaymentOptionsContext(ctx context.Context, filter *accountsservice.Filter) ([]accountsservice.PaymentOption, error) {
+	m.record("GetPaymentOptionsContext", ctx, filter)
+
+	if m.GetPaymentOptionsContextFunc != nil {
+		return m.GetPaymentOptionsContextFunc(ctx, filter)
+	}
+
+	return nil, notConfigured("GetPaymentOptionsContext")
+}
+
+func (m *Mock) CreatePaymentOption(paymentOption *accountsservice.NewPaymentOption) (*accountsservice.PaymentOption, error) {
+	m.record("CreatePaymentOption", paymentOption)
+
+	if m.CreatePaymentOptionFunc != nil {
+		return m.CreatePaymentOptionFunc(paymentOption)
+	}
+
+	if m.CreatePaymentOptionContextFunc != nil {
+		return m.CreatePaymentOptionContextFunc(context.Background(), paymentOption)
+	}
+
+	return nil, notConfigured("CreatePaymentOption")
+}
+
+func (m *Mock) CreatePaymentOptionContext(ctx context.Context, paymentOption *accountsservice.NewPaymentOption) (*accountsservice.PaymentOption, error) {
+	m.record("CreatePaymentOptionContext", ctx, paymentOption)
+
+	if m.CreatePaymentOptionContextFunc != nil {
+		return m.CreatePaymentOptionContextFunc(ctx, paymentOption)
+	}
+
+	return nil, notConfigured("CreatePaymentOptionContext")
+}
+
+func (m *Mock) UpdatePaymentOptionExpiry(paymentOptionId int, expMonth string, expYear string) (*accountsservice.PaymentOption, error) {
+	m.record("UpdatePaymentOptionExpiry", paymentOptionId, expMonth, expYear)
+
+	if m.UpdatePaymentOptionExpiryFunc != nil {
+		return m.UpdatePaymentOptionExpiryFunc(paymentOptionId, expMonth, expYear)
+	}
+
+	if m.UpdatePaymentOptionExpiryContextFunc != nil {
+		return m.UpdatePaymentOptionExpiryContextFunc(context.Background(), paymentOptionId, expMonth, expYear)
+	}
+
+	return nil, notConfigured("UpdatePaymentOptionExpiry")
+}
+
+func (m *Mock) UpdatePaymentOptionExpiryContext(ctx context.Context, paymentOptionId int, expMonth string, expYear string) (*accountsservice.PaymentOption, error) {
+	m.record("UpdatePaymentOptionExpiryContext", ctx, paymentOptionId, expMonth, expYear)
+
+	if m.UpdatePaymentOptionExpiryContextFunc != nil {
+		return m.UpdatePaymentOptionExpiryContextFunc(ctx, paymentOptionId, expMonth, expYear)
+	}
+
+	return nil, notConfigured("UpdatePaymentOptionExpiryContext")
+}
+
+func (m *Mock) SetDefaultPaymentOption(paymentOptionId int) (*accountsservice.PaymentOption, error) {
+	m.record("SetDefaultPaymentOption", paymentOptionId)
+
+	if m.SetDefaultPaymentOptionFunc != nil {
+		return m.SetDefaultPaymentOptionFunc(paymentOptionId)
+	}
+
+	if m.SetDefaultPaymentOptionContextFunc != nil {
+		return m.SetDefaultPaymentOptionContextFunc(context.Background(), paymentOptionId)
+	}
+
+	return nil, notConfigured("SetDefaultPaymentOption")
+}
+
+func (m *Mock) SetDefaultPaymentOptionContext(ctx context.Context, paymentOptionId int) (*accountsservice.PaymentOption, error) {
+	m.record("SetDefaultPaymentOptionContext", ctx, paymentOptionId)
+
+	if m.SetDefaultPaymentOptionContextFunc != nil {
+		return m.SetDefaultPaymentOptionContextFunc(ctx, paymentOptionId)
+	}
+
+	return nil, notConfigured("SetDefaultPaymentOptionContext")
+}
+
+func (m *Mock) DeactivatePaymentOption(paymentOptionId int) (*accountsservice.PaymentOption, error) {
+	m.record("DeactivatePaymentOption", paymentOptionId)
+
+	if m.DeactivatePaymentOptionFunc != nil {
+		return m.DeactivatePaymentOptionFunc(paymentOptionId)
+	}
+
+	if m.DeactivatePaymentOptionContextFunc != nil {
+		return m.DeactivatePaymentOptionContextFunc(context.Background(), paymentOptionId)
+	}
+
+	return nil, notConfigured("DeactivatePaymentOption")
+}
+
+func (m *Mock) DeactivatePaymentOptionContext(ctx context.Context, paymentOptionId int) (*accountsservice.PaymentOption, error) {
+	m.record("DeactivatePaymentOptionContext", ctx, paymentOptionId)
+
+	if m.DeactivatePaymentOptionContextFunc != nil {
+		return m.DeactivatePaymentOptionContextFunc(ctx, paymentOptionId)
+	}
+
+	return nil, notConfigured("DeactivatePaymentOptionContext")
+}
+
+func (m *Mock) GetTransactions(filter *accountsservice.Filter) ([]accountsservice.Transaction, error) {
+	m.record("GetTransactions", filter)
+
+	if m.GetTransactionsFunc != nil {
+		return m.GetTransactionsFunc(filter)
+	}
+
+	if m.GetTransactionsContextFunc != nil {
+		return m.GetTransactionsContextFunc(context.Background(), filter)
+	}
+
+	return nil, notConfigured("GetTransactions")
+}
+
+func (m *Mock) GetTransactionsContext(ctx context.Context, filter *accountsservice.Filter) ([]accountsservice.Transaction, error) {
+	m.record("GetTransactionsContext", ctx, filter)
+
+	if m.GetTransactionsContextFunc != nil {
+		return m.GetTransactionsContextFunc(ctx, filter)
+	}
+
+	return nil, notConfigured("GetTransactionsContext")
+}
+
+func (m *Mock) RefundTransaction(transaction *accountsservice.Transaction, amount *currency.Amount, reason string) (*accountsservice.Transaction, error) {
+	m.record("RefundTransaction", transaction, amount, reason)
+
+	if m.RefundTransactionFunc != nil {
+		return m.RefundTransactionFunc(transaction, amount, reason)
+	}
+
+	if m.RefundTransactionContextFunc != nil {
+		return m.RefundTransactionContextFunc(context.Background(), transaction, amount, reason)
+	}
+
+	return nil, notConfigured("RefundTransaction")
+}
+
+func (m *Mock) RefundTransactionContext(ctx context.Context, transaction *accountsservice.Transaction, amount *currency.Amount, reason string) (*accountsservice.Transaction, error) {
+	m.record("RefundTransactionContext", ctx, transaction, amount, reason)
+
+	if m.RefundTransactionContextFunc != nil {
+		return m.RefundTransactionContextFunc(ctx, transaction, amount, reason)
+	}
+
+	return nil, notConfigured("RefundTransactionContext")
+}
+
+func (m *Mock) VoidTransaction(transactionId int, reason string) (*accountsservice.Transaction, error) {
+	m.record("VoidTransaction", transactionId, reason)
+
+	if m.VoidTransactionFunc != nil {
+		return m.VoidTransactionFunc(transactionId, reason)
+	}
+
+	if m.VoidTransactionContextFunc != nil {
+		return m.VoidTransactionContextFunc(context.Background(), transactionId, reason)
+	}
+
+	return nil, notConfigured("VoidTransaction")
+}
+
+func (m *Mock) VoidTransactionContext(ctx context.Context, transactionId int, reason string) (*accountsservice.Transaction, error) {
+	m.record("VoidTransactionContext", ctx, transactionId, reason)
+
+	if m.VoidTransactionContextFunc != nil {
+		return m.VoidTransactionContextFunc(ctx, transactionId, reason)
+	}
+
+	return nil, notConfigured("VoidTransactionContext")
+}
+
+func (m *Mock) ListCustomerOrders(ctx context.Context, customerId int, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Order] {
+	m.record("ListCustomerOrders", ctx, customerId, filter)
+
+	if m.ListCustomerOrdersFunc != nil {
+		return m.ListCustomerOrdersFunc(ctx, customerId, filter)
+	}
+
+	return failing[accountsservice.Order](ctx, notConfigured("ListCustomerOrders"))
+}
+
+func (m *Mock) ListCustomerPaymentOptions(ctx context.Context, customerId int, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.PaymentOption] {
+	m.record("ListCustomerPaymentOptions", ctx, customerId, filter)
+
+	if m.ListCustomerPaymentOptionsFunc != nil {
+		return m.ListCustomerPaymentOptionsFunc(ctx, customerId, filter)
+	}
+
+	return failing[accountsservice.PaymentOption](ctx, notConfigured("ListCustomerPaymentOptions"))
+}
+
+func (m *Mock) ListCustomerTransactions(ctx context.Context, customerId int, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Transaction] {
+	m.record("ListCustomerTransactions", ctx, customerId, filter)
+
+	if m.ListCustomerTransactionsFunc != nil {
+		return m.ListCustomerTransactionsFunc(ctx, customerId, filter)
+	}
+
+	return failing[accountsservice.Transaction](ctx, notConfigured("ListCustomerTransactions"))
+}
+
+func (m *Mock) ListOrderSubscriptions(ctx context.Context, orderId int, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Subscription] {
+	m.record("ListOrderSubscriptions", ctx, orderId, filter)
+
+	if m.ListOrderSubscriptionsFunc != nil {
+		return m.ListOrderSubscriptionsFunc(ctx, orderId, filter)
+	}
+
+	return failing[accountsservice.Subscription](ctx, notConfigured("ListOrderSubscriptions"))
+}
+
+func (m *Mock) ListSubscriptionOrders(ctx context.Context, subscriptionId int, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Order] {
+	m.record("ListSubscriptionOrders", ctx, subscriptionId, filter)
+
+	if m.ListSubscriptionOrdersFunc != nil {
+		return m.ListSubscriptionOrdersFunc(ctx, subscriptionId, filter)
+	}
+
+	return failing[accountsservice.Order](ctx, notConfigured("ListSubscriptionOrders"))
+}
+
+func (m *Mock) ListPaymentOptions(ctx context.Context, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.PaymentOption] {
+	m.record("ListPaymentOptions", ctx, filter)
+
+	if m.ListPaymentOptionsFunc != nil {
+		return m.ListPaymentOptionsFunc(ctx, filter)
+	}
+
+	return failing[accountsservice.PaymentOption](ctx, notConfigured("ListPaymentOptions"))
+}
+
+func (m *Mock) ListTransactions(ctx context.Context, filter *accountsservice.Filter) *accountsservice.Iterator[accountsservice.Transaction] {
+	m.record("ListTransactions", ctx, filter)
+
+	if m.ListTransactionsFunc != nil {
+		return m.ListTransactionsFunc(ctx, filter)
+	}
+
+	return failing[accountsservice.Transaction](ctx, notConfigured("ListTransactions"))
+}
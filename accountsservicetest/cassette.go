@@ -0,0 +1,392 @@
+package accountsservicetest
+
+// stdlib
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+)
+
+// Redacted replaces redacted header and field values in cassettes.
+const Redacted = "REDACTED"
+
+// DefaultRedactedHeaders are the headers redacted from recorded requests and
+// responses.
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultRedactedFields are the json fields, at any depth, redacted from
+// recorded bodies, and the fields whose filter values are redacted from
+// recorded queries.
+var DefaultRedactedFields = []string{
+	"first_name", "last_name", "email", "phone", "ip", "login.latest_ip",
+	"bin", "last4", "card_name", "token",
+}
+
+// CassetteMode is whether a cassette records or replays.
+type CassetteMode int
+
+const (
+	ModeReplay CassetteMode = iota
+	ModeRecord
+)
+
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  url.Values  `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   interface{} `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+// Interaction is a recorded request and its response. Json bodies are stored
+// decoded so cassettes are readable and diffable; other bodies are stored as
+// strings.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// UnmatchedRequestError is returned by a replaying cassette for a request
+// that no unplayed interaction matches.
+type UnmatchedRequestError struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+func (e *UnmatchedRequestError) Error() string {
+	if e.Query == "" {
+		return fmt.Sprintf("accountsservicetest: no recorded interaction for %s %s", e.Method, e.Path)
+	}
+	return fmt.Sprintf("accountsservicetest: no recorded interaction for %s %s?%s", e.Method, e.Path, e.Query)
+}
+
+// Cassette records requests and responses to a file, or replays them from
+// one, for golden tests against a real accounts service:
+//
+//	cassette, err := accountsservicetest.LoadCassette("testdata/checkout.json")
+//	client := accountsservice.NewClient(accountsservice.WithMiddleware(cassette.Middleware()))
+//
+// Replayed requests match an interaction on method, path and normalized
+// query. Each interaction is played once, in recorded order among the ones
+// matching. Requests without a match fail with *UnmatchedRequestError.
+type Cassette struct {
+	Path         string
+	Mode         CassetteMode
+	Interactions []Interaction
+
+	// RedactHeaders and RedactFields are applied when recording. Change them
+	// before the cassette is used.
+	RedactHeaders []string
+	RedactFields  []string
+
+	mu     sync.Mutex
+	played []bool
+}
+
+// RecordCassette returns a cassette that passes requests on and records
+// them. Call Save to write it to path.
+func RecordCassette(path string) *Cassette {
+	return &Cassette{
+		Path:          path,
+		Mode:          ModeRecord,
+		RedactHeaders: DefaultRedactedHeaders,
+		RedactFields:  DefaultRedactedFields,
+	}
+}
+
+// LoadCassette reads the cassette at path for replay.
+func LoadCassette(path string) (cassette *Cassette, err error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return
+	}
+
+	cassette = &Cassette{
+		Path:          path,
+		Mode:          ModeReplay,
+		RedactHeaders: DefaultRedactedHeaders,
+		RedactFields:  DefaultRedactedFields,
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err = decoder.Decode(&cassette.Interactions); err != nil {
+		return nil, fmt.Errorf("accountsservicetest: invalid cassette %s: %w", path, err)
+	}
+
+	return
+}
+
+// Save writes the recorded interactions to Path, creating its directory.
+func (c *Cassette) Save() (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var data bytes.Buffer
+
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(c.Interactions); err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return
+	}
+
+	return ioutil.WriteFile(c.Path, data.Bytes(), 0644)
+}
+
+// Unplayed returns the interactions a replaying cassette hasn't played yet.
+func (c *Cassette) Unplayed() (interactions []Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.Interactions {
+		if i >= len(c.played) || !c.played[i] {
+			interactions = append(interactions, interaction)
+		}
+	}
+	return
+}
+
+// Middleware records or replays the requests of a client, depending on Mode.
+// When replaying, requests never reach the next transport.
+func (c *Cassette) Middleware() accountsservice.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return accountsservice.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if c.Mode == ModeRecord {
+				return c.record(next, req)
+			}
+			return c.replay(req)
+		})
+	}
+}
+
+func (c *Cassette) record(next http.RoundTripper, req *http.Request) (resp *http.Response, err error) {
+	var reqBody []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	if resp, err = next.RoundTrip(req); err != nil {
+		return
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  c.redactQuery(req.URL.Query()),
+			Header: c.redactHeader(req.Header),
+			Body:   c.body(req.Header, reqBody),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     c.redactHeader(resp.Header),
+			Body:       c.body(resp.Header, respBody),
+		},
+	}
+
+	interaction.Response.Header.Del("Content-Length")
+
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, interaction)
+	c.mu.Unlock()
+
+	return
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	query := c.redactQuery(req.URL.Query()).Encode()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.played) < len(c.Interactions) {
+		c.played = append(c.played, make([]bool, len(c.Interactions)-len(c.played))...)
+	}
+
+	for i, interaction := range c.Interactions {
+		recorded := interaction.Request
+
+		if c.played[i] || recorded.Method != req.Method || recorded.Path != req.URL.Path || c.redactQuery(recorded.Query).Encode() != query {
+			continue
+		}
+
+		c.played[i] = true
+
+		body, err := encodeBody(interaction.Response)
+
+		if err != nil {
+			return nil, err
+		}
+
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, &UnmatchedRequestError{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  query,
+	}
+}
+
+// redactQuery redacts the values of filters on redacted fields, including
+// nested ones like payment_processor_details.bin. Encoding the result sorts it
+// by key, which normalizes it for matching.
+func (c *Cassette) redactQuery(values url.Values) url.Values {
+	for key := range values {
+		if match := filterKey.FindStringSubmatch(key); match != nil && c.redactedPath(match[1]) {
+			for i := range values[key] {
+				values[key][i] = Redacted
+			}
+		}
+	}
+	return values
+}
+
+func (c *Cassette) redacted(field string) bool {
+	for _, redacted := range c.RedactFields {
+		if strings.EqualFold(field, redacted) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedPath reports whether a dotted field path ends in a redacted field.
+func (c *Cassette) redactedPath(path string) bool {
+	for {
+		if c.redacted(path) {
+			return true
+		}
+
+		i := strings.Index(path, ".")
+		if i < 0 {
+			return false
+		}
+
+		path = path[i+1:]
+	}
+}
+
+func (c *Cassette) redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+
+	for _, name := range c.RedactHeaders {
+		if _, ok := header[http.CanonicalHeaderKey(name)]; ok {
+			header.Set(name, Redacted)
+		}
+	}
+
+	return header
+}
+
+// body decodes a json body and redacts it. Other bodies are kept as strings.
+func (c *Cassette) body(header http.Header, data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var v interface{}
+		if decoder.Decode(&v) == nil {
+			return c.redact(v)
+		}
+	}
+
+	return string(data)
+}
+
+func (c *Cassette) redact(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if str, isString := item.(string); isString && str != "" && c.redacted(key) {
+				value[key] = Redacted
+			} else {
+				value[key] = c.redact(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = c.redact(item)
+		}
+	}
+	return v
+}
+
+// encodeBody turns a recorded body back into bytes.
+func encodeBody(resp CassetteResponse) ([]byte, error) {
+	switch body := resp.Body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(body), nil
+	}
+	return json.Marshal(resp.Body)
+}
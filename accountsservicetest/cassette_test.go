@@ -0,0 +1,159 @@
+package accountsservicetest
+
+// stdlib
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+)
+
+// recordSession records client calls against a fake server holding private
+// customer data and saves them to path.
+func recordSession(t *testing.T, path string) {
+	t.Helper()
+
+	s := NewServer()
+	defer s.Close()
+
+	phone := "555-0100"
+	s.AddCustomer(accountsservice.Customer{
+		Id:        1,
+		FirstName: "Annabel",
+		LastName:  "Lee",
+		Email:     "annabel@example.com",
+		Phone:     &phone,
+		Data:      accountsservice.CustomerData{LoginLatestIp: "203.0.113.7"},
+	})
+
+	cassette := RecordCassette(path)
+	client := s.Client(
+		accountsservice.WithAuthorization("Bearer secret-token"),
+		accountsservice.WithMiddleware(cassette.Middleware()),
+	)
+
+	if _, err := client.GetCustomer(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	email := "lee@example.com"
+	if _, err := client.UpdateCustomer(1, &accountsservice.CustomerUpdate{Email: &email}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	filter := accountsservice.NewFilter().Eq("payment_processor_details.bin", "411111").Eq("status", "approved")
+	if _, err := client.GetTransactions(filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := cassette.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCassetteRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "customer.json")
+
+	recordSession(t, path)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, private := range []string{"secret-token", "Annabel", `"Lee"`, "annabel@example.com", "lee@example.com", "555-0100", "203.0.113.7", "411111"} {
+		if strings.Contains(string(data), private) {
+			t.Errorf("cassette contains %s", private)
+		}
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cassette.Interactions) != 3 {
+		t.Fatalf("got %d interactions, want 3", len(cassette.Interactions))
+	}
+
+	if header := cassette.Interactions[0].Request.Header.Get("Authorization"); header != Redacted {
+		t.Errorf("got Authorization %q, want %s", header, Redacted)
+	}
+
+	if status := cassette.Interactions[2].Request.Query.Get("filter[status][eq]"); status != "approved" {
+		t.Errorf("got status filter %q, want it kept", status)
+	}
+}
+
+func TestCassetteReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customer.json")
+
+	recordSession(t, path)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Replayed requests never reach the network.
+	unreachable := accountsservice.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("request %s %s reached the transport", req.Method, req.URL)
+		return nil, errors.New("unreachable")
+	})
+	transport := cassette.Middleware()(unreachable)
+
+	client := accountsservice.NewClient(
+		accountsservice.WithBaseUrl("http://accounts.invalid"),
+		accountsservice.WithMiddleware(cassette.Middleware()),
+	)
+
+	customer, err := client.GetCustomer(1)
+	if err != nil || customer.Id != 1 || customer.Email != Redacted {
+		t.Fatalf("got %+v, %v, want the recorded customer", customer, err)
+	}
+
+	// Queries match whatever their order and redacted values.
+	req, _ := http.NewRequest(http.MethodGet, "http://accounts.invalid/v1/transactions?filter[status][eq]=approved&filter[payment_processor_details.bin][eq]=555555", nil)
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("got %v, %v, want the recorded transactions", resp, err)
+	}
+	resp.Body.Close()
+
+	unplayed := cassette.Unplayed()
+
+	if len(unplayed) != 1 || unplayed[0].Request.Method != http.MethodPatch {
+		t.Fatalf("got %d unplayed interactions, want the PATCH", len(unplayed))
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want UnmatchedRequestError
+	}{
+		{"already played", "/v1/customers/1", UnmatchedRequestError{Method: "GET", Path: "/v1/customers/1"}},
+		{"other path", "/v1/customers/2", UnmatchedRequestError{Method: "GET", Path: "/v1/customers/2"}},
+		{"other query", "/v1/transactions?filter[status][eq]=declined", UnmatchedRequestError{Method: "GET", Path: "/v1/transactions", Query: "filter%5Bstatus%5D%5Beq%5D=declined"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "http://accounts.invalid"+test.url, nil)
+
+			_, err := transport.RoundTrip(req)
+
+			var unmatched *UnmatchedRequestError
+			if !errors.As(err, &unmatched) || *unmatched != test.want {
+				t.Fatalf("got %v, want %v", err, &test.want)
+			}
+		})
+	}
+}
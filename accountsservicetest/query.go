@@ -0,0 +1,323 @@
+package accountsservicetest
+
+// stdlib
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// record is a resource marshaled to its json representation, which is what
+// filters, sorting and aggregation operate on.
+type record map[string]interface{}
+
+func toRecord(v interface{}) record {
+	data, _ := json.Marshal(v)
+	r := record{}
+	json.Unmarshal(data, &r)
+	return r
+}
+
+// lookup returns the value of a possibly dotted field. Keys containing dots,
+// like the customer data keys, take precedence over nested lookups.
+func (r record) lookup(field string) (value interface{}, ok bool) {
+	if value, ok = r[field]; ok {
+		return
+	}
+
+	if i := strings.Index(field, "."); i > 0 {
+		if nested, isMap := r[field[:i]].(map[string]interface{}); isMap {
+			return record(nested).lookup(field[i+1:])
+		}
+	}
+
+	return nil, false
+}
+
+type condition struct {
+	field    string
+	operator string
+	value    string
+}
+
+var filterKey = regexp.MustCompile(`^filter\[([^\]]+)\]\[([^\]]+)\]$`)
+
+// query is a parsed list request.
+type query struct {
+	conditions []condition
+	sort       []string
+	fields     []string
+	limit      int
+	offset     int
+}
+
+func parseQuery(values url.Values) (q query, err error) {
+	for key, vals := range values {
+		if match := filterKey.FindStringSubmatch(key); match != nil {
+			for _, value := range vals {
+				q.conditions = append(q.conditions, condition{match[1], match[2], value})
+			}
+		}
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		q.sort = strings.Split(sort, ",")
+	}
+
+	if fields := values.Get("fields"); fields != "" {
+		q.fields = strings.Split(fields, ",")
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		if q.limit, err = strconv.Atoi(limit); err != nil || q.limit < 0 {
+			return q, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+
+	if offset := values.Get("offset"); offset != "" {
+		if q.offset, err = strconv.Atoi(offset); err != nil || q.offset < 0 {
+			return q, fmt.Errorf("invalid offset %q", offset)
+		}
+	}
+
+	return
+}
+
+// apply filters, sorts, pages and projects records.
+func (q query) apply(records []record) (result []record, err error) {
+	for _, r := range records {
+		var ok bool
+		if ok, err = q.matches(r); err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, r)
+		}
+	}
+
+	if len(q.sort) > 0 {
+		sort.SliceStable(result, func(i, j int) bool {
+			for _, field := range q.sort {
+				descending := strings.HasPrefix(field, "-")
+				field = strings.TrimPrefix(field, "-")
+				a, _ := result[i].lookup(field)
+				b, _ := result[j].lookup(field)
+				if c := compare(a, fmt.Sprint(b)); c != 0 && b != nil {
+					return (c < 0) != descending
+				}
+			}
+			return false
+		})
+	}
+
+	if q.offset >= len(result) {
+		result = []record{}
+	} else {
+		result = result[q.offset:]
+	}
+
+	if q.limit > 0 && q.limit < len(result) {
+		result = result[:q.limit]
+	}
+
+	if len(q.fields) > 0 {
+		for i, r := range result {
+			projected := record{}
+			for _, field := range q.fields {
+				if value, ok := r.lookup(field); ok {
+					projected[field] = value
+				}
+			}
+			result[i] = projected
+		}
+	}
+
+	return
+}
+
+func (q query) matches(r record) (bool, error) {
+	for _, c := range q.conditions {
+		value, _ := r.lookup(c.field)
+
+		var ok bool
+
+		switch c.operator {
+		case "eq":
+			ok = equal(value, c.value)
+		case "ne":
+			ok = !equal(value, c.value)
+		case "gt":
+			ok = value != nil && compare(value, c.value) > 0
+		case "gte":
+			ok = value != nil && compare(value, c.value) >= 0
+		case "lt":
+			ok = value != nil && compare(value, c.value) < 0
+		case "lte":
+			ok = value != nil && compare(value, c.value) <= 0
+		case "in", "nin":
+			for _, item := range strings.Split(c.value, ",") {
+				if equal(value, item) {
+					ok = true
+					break
+				}
+			}
+			if c.operator == "nin" {
+				ok = !ok
+			}
+		case "like":
+			pattern := "^" + strings.Replace(regexp.QuoteMeta(c.value), "%", ".*", -1) + "$"
+			ok = value != nil && regexp.MustCompile(pattern).MatchString(fmt.Sprint(value))
+		case "null":
+			null, err := strconv.ParseBool(c.value)
+			if err != nil {
+				return false, fmt.Errorf("invalid null filter value %q", c.value)
+			}
+			ok = (value == nil) == null
+		default:
+			return false, fmt.Errorf("unknown operator %q", c.operator)
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func equal(value interface{}, filter string) bool {
+	if value == nil {
+		return filter == "null"
+	}
+	return compare(value, filter) == 0
+}
+
+// compare orders a json value against a filter value, comparing numbers,
+// times and bools by value and everything else as strings.
+func compare(value interface{}, filter string) int {
+	switch v := value.(type) {
+	case float64:
+		if f, err := strconv.ParseFloat(filter, 64); err == nil {
+			switch {
+			case v < f:
+				return -1
+			case v > f:
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if b, err := strconv.ParseBool(filter); err == nil {
+			if v == b {
+				return 0
+			}
+			if !v {
+				return -1
+			}
+			return 1
+		}
+	case string:
+		if a, ok := parseTime(v); ok {
+			if b, ok := parseTime(filter); ok {
+				switch {
+				case a.Before(b):
+					return -1
+				case a.After(b):
+					return 1
+				}
+				return 0
+			}
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(value), filter)
+}
+
+func parseTime(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+var aggregateExpression = regexp.MustCompile(`^(count|sum|avg|min|max)(?:\(([^)]*)\))?$`)
+
+// aggregate groups records by the group field and computes each aggregate
+// expression per group. Expressions are count, sum(field), avg(field),
+// min(field) and max(field).
+func aggregate(records []record, group string, expressions []string) (map[string]interface{}, error) {
+	groups := map[string][]record{}
+
+	for _, r := range records {
+		key := "null"
+		if value, ok := r.lookup(group); ok && value != nil {
+			key = fmt.Sprint(value)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	result := map[string]interface{}{}
+
+	for key, members := range groups {
+		values := map[string]interface{}{}
+
+		for _, expression := range expressions {
+			match := aggregateExpression.FindStringSubmatch(expression)
+
+			if match == nil || match[1] != "count" && match[2] == "" {
+				return nil, fmt.Errorf("invalid aggregate %q", expression)
+			}
+
+			if match[1] == "count" {
+				values[expression] = len(members)
+				continue
+			}
+
+			var sum, min, max float64
+			var n int
+
+			for _, r := range members {
+				value, _ := r.lookup(match[2])
+				f, ok := value.(float64)
+				if !ok {
+					continue
+				}
+				if n == 0 || f < min {
+					min = f
+				}
+				if n == 0 || f > max {
+					max = f
+				}
+				sum += f
+				n++
+			}
+
+			switch match[1] {
+			case "sum":
+				values[expression] = sum
+			case "avg":
+				if n > 0 {
+					values[expression] = sum / float64(n)
+				} else {
+					values[expression] = nil
+				}
+			case "min":
+				values[expression] = min
+			case "max":
+				values[expression] = max
+			}
+		}
+
+		result[key] = values
+	}
+
+	return result, nil
+}
@@ -0,0 +1,176 @@
+package accountsservicetest
+
+// stdlib
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var records = []record{
+	{"id": 1.0, "status": "active", "amount": 9.99, "created": "2026-01-01T10:00:00Z", "email": "ann@example.com", "canceled": nil, "default": true, "data": map[string]interface{}{"source": "web"}},
+	{"id": 2.0, "status": "canceled", "amount": 19.99, "created": "2026-02-01T10:00:00Z", "email": "bob@test.org", "canceled": "2026-03-01T00:00:00Z", "default": false, "data": map[string]interface{}{"source": "app"}},
+	{"id": 3.0, "status": "active", "amount": 5.0, "created": "2026-03-01T10:00:00Z", "email": "cy@example.com", "canceled": nil, "default": false, "data.source": "import"},
+	{"id": 4.0, "status": "paused", "amount": 19.99, "created": "2026-04-01T10:00:00Z", "email": "di@example.com", "canceled": nil, "default": false},
+}
+
+// ids returns the ids of records, or the records themselves when they were
+// projected without their id.
+func ids(records []record) (result []interface{}) {
+	for _, r := range records {
+		if id, ok := r["id"]; ok {
+			result = append(result, int(id.(float64)))
+		} else {
+			result = append(result, map[string]interface{}(r))
+		}
+	}
+	return
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []interface{}
+	}{
+		{"", []interface{}{1, 2, 3, 4}},
+		{"filter[status][eq]=active", []interface{}{1, 3}},
+		{"filter[status][ne]=active", []interface{}{2, 4}},
+		{"filter[amount][gt]=9.99", []interface{}{2, 4}},
+		{"filter[amount][gte]=9.99&filter[amount][lt]=19.99", []interface{}{1}},
+		{"filter[amount][lte]=9.99", []interface{}{1, 3}},
+		{"filter[created][gte]=2026-02-01&filter[created][lt]=2026-04-01", []interface{}{2, 3}},
+		{"filter[created][lt]=2026-02-01T10:00:00Z", []interface{}{1}},
+		{"filter[id][in]=1,3,5", []interface{}{1, 3}},
+		{"filter[status][nin]=active,paused", []interface{}{2}},
+		{"filter[email][like]=%25@example.com", []interface{}{1, 3, 4}},
+		{"filter[email][like]=b%25", []interface{}{2}},
+		{"filter[email][like]=.%2A", nil},
+		{"filter[canceled][null]=true", []interface{}{1, 3, 4}},
+		{"filter[canceled][null]=false", []interface{}{2}},
+		{"filter[canceled][eq]=null", []interface{}{1, 3, 4}},
+		{"filter[default][eq]=true", []interface{}{1}},
+		{"filter[data.source][eq]=web", []interface{}{1}},
+		{"filter[data.source][eq]=import", []interface{}{3}},
+		{"filter[data.source][null]=true", []interface{}{4}},
+		{"filter[missing][gt]=1", nil},
+		{"sort=-amount,id", []interface{}{2, 4, 1, 3}},
+		{"sort=status,-created", []interface{}{3, 1, 2, 4}},
+		{"sort=-id&limit=2", []interface{}{4, 3}},
+		{"limit=2&offset=1", []interface{}{2, 3}},
+		{"offset=10", nil},
+		{"filter[status][eq]=active&fields=email,data.source", []interface{}{
+			map[string]interface{}{"email": "ann@example.com", "data.source": "web"},
+			map[string]interface{}{"email": "cy@example.com", "data.source": "import"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			q, err := parseQuery(values)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			result, err := q.apply(append([]record(nil), records...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := ids(result); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{"limit=ten", `invalid limit "ten"`},
+		{"limit=-1", `invalid limit "-1"`},
+		{"offset=-5", `invalid offset "-5"`},
+		{"filter[status][regex]=a.*", `unknown operator "regex"`},
+		{"filter[canceled][null]=maybe", `invalid null filter value "maybe"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(test.query)
+
+			q, err := parseQuery(values)
+			if err == nil {
+				_, err = q.apply(records)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got %v, want %s", err, test.err)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		group       string
+		expressions []string
+		want        map[string]interface{}
+		err         string
+	}{
+		{
+			group:       "status",
+			expressions: []string{"count", "sum(amount)"},
+			want: map[string]interface{}{
+				"active":   map[string]interface{}{"count": 2, "sum(amount)": 14.99},
+				"canceled": map[string]interface{}{"count": 1, "sum(amount)": 19.99},
+				"paused":   map[string]interface{}{"count": 1, "sum(amount)": 19.99},
+			},
+		},
+		{
+			group:       "default",
+			expressions: []string{"min(amount)", "max(amount)", "avg(id)"},
+			want: map[string]interface{}{
+				"true":  map[string]interface{}{"min(amount)": 9.99, "max(amount)": 9.99, "avg(id)": 1.0},
+				"false": map[string]interface{}{"min(amount)": 5.0, "max(amount)": 19.99, "avg(id)": 3.0},
+			},
+		},
+		{
+			group:       "canceled",
+			expressions: []string{"count", "avg(email)"},
+			want: map[string]interface{}{
+				"null":                 map[string]interface{}{"count": 3, "avg(email)": nil},
+				"2026-03-01T00:00:00Z": map[string]interface{}{"count": 1, "avg(email)": nil},
+			},
+		},
+		{group: "status", expressions: []string{"sum"}, err: `invalid aggregate "sum"`},
+		{group: "status", expressions: []string{"median(amount)"}, err: `invalid aggregate "median(amount)"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.group+" "+strings.Join(test.expressions, " "), func(t *testing.T) {
+			got, err := aggregate(records, test.group, test.expressions)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("got %v, want %s", err, test.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
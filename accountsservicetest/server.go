@@ -0,0 +1,997 @@
+// Package accountsservicetest provides an in-memory fake of the accounts
+// service for testing code that uses the accountsservice client offline.
+//
+//	server := accountsservicetest.NewServer()
+//	defer server.Close()
+//
+//	server.AddCustomer(accountsservice.Customer{Id: 1, Email: "a@example.com"})
+//	client := server.Client()
+//	customer, err := client.GetCustomer(1)
+//
+// Cassette records the traffic of a client against a real accounts service
+// and replays it later, for golden tests.
+package accountsservicetest
+
+// stdlib
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+	"github.com/the-control-group/go-currency"
+)
+
+// Fault makes matching requests fail, to test how code copes with errors from
+// the accounts service.
+type Fault struct {
+	// Method matches the request method. Empty matches any method.
+	Method string
+	// Path matches the request path exactly, or as a prefix when it ends in
+	// *. Empty matches any path.
+	Path string
+	// StatusCode of the error response. Defaults to 500.
+	StatusCode int
+	Code       string
+	Message    string
+	Failures   []map[string]interface{}
+	// Header is added to the error response, e.g. Retry-After.
+	Header http.Header
+	// Times is the number of requests to fail. Zero fails every request.
+	Times int
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+	if strings.HasSuffix(f.Path, "*") {
+		return strings.HasPrefix(r.URL.Path, strings.TrimSuffix(f.Path, "*"))
+	}
+	return f.Path == "" || f.Path == r.URL.Path
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Server is an httptest.Server backed by an in-memory store of customers,
+// orders, subscriptions, transactions, payment options, plans and products.
+// It supports the read and write endpoints of the client, the
+// filter[field][op] query syntax with sort, fields, limit and offset,
+// transaction aggregation and replay of requests with an Idempotency-Key.
+type Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	nextId         int
+	requests       int
+	customers      map[int]*accountsservice.Customer
+	orders         map[int]*accountsservice.Order
+	subscriptions  map[int]*accountsservice.Subscription
+	transactions   map[int]*accountsservice.Transaction
+	paymentOptions map[int]*accountsservice.PaymentOption
+	plans          map[string]*accountsservice.Plan
+	products       map[string]*accountsservice.Product
+	faults         []*Fault
+	idempotent     map[string]response
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		nextId:         1,
+		customers:      map[int]*accountsservice.Customer{},
+		orders:         map[int]*accountsservice.Order{},
+		subscriptions:  map[int]*accountsservice.Subscription{},
+		transactions:   map[int]*accountsservice.Transaction{},
+		paymentOptions: map[int]*accountsservice.PaymentOption{},
+		plans:          map[string]*accountsservice.Plan{},
+		products:       map[string]*accountsservice.Product{},
+		idempotent:     map[string]response{},
+	}
+
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// Client returns a client talking to the server. Options are applied after
+// the base url.
+func (s *Server) Client(options ...accountsservice.Option) *accountsservice.Client {
+	return accountsservice.NewClient(append([]accountsservice.Option{
+		accountsservice.WithBaseUrl(s.URL),
+		accountsservice.WithHttpClient(s.Server.Client()),
+	}, options...)...)
+}
+
+// InjectFault makes requests matching fault fail until it is used up or
+// ClearFaults is called. Faults are checked in the order they were injected.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fault.StatusCode == 0 {
+		fault.StatusCode = http.StatusInternalServerError
+	}
+
+	s.faults = append(s.faults, &fault)
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// id returns id, or the next free id when it is zero.
+func (s *Server) id(id int) int {
+	if id == 0 {
+		id = s.nextId
+	}
+	if id >= s.nextId {
+		s.nextId = id + 1
+	}
+	return id
+}
+
+func stamp(created, updated *time.Time) {
+	now := time.Now().UTC().Truncate(time.Second)
+	if created.IsZero() {
+		*created = now
+	}
+	if updated.IsZero() {
+		*updated = now
+	}
+}
+
+// AddCustomer stores customer, assigning an id and timestamps when missing,
+// and returns it as stored.
+func (s *Server) AddCustomer(customer accountsservice.Customer) accountsservice.Customer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customer.Id = s.id(customer.Id)
+	stamp(&customer.Created, &customer.Updated)
+	s.customers[customer.Id] = &customer
+
+	return customer
+}
+
+func (s *Server) AddOrder(order accountsservice.Order) accountsservice.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order.Id = s.id(order.Id)
+	stamp(&order.Created, &order.Updated)
+	s.orders[order.Id] = &order
+
+	return order
+}
+
+func (s *Server) AddSubscription(subscription accountsservice.Subscription) accountsservice.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription.Id = s.id(subscription.Id)
+	stamp(&subscription.Created, &subscription.Updated)
+	s.subscriptions[subscription.Id] = &subscription
+
+	return subscription
+}
+
+func (s *Server) AddTransaction(transaction accountsservice.Transaction) accountsservice.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transaction.Id = s.id(transaction.Id)
+	stamp(&transaction.Created, &transaction.Updated)
+	s.transactions[transaction.Id] = &transaction
+
+	return transaction
+}
+
+func (s *Server) AddPaymentOption(paymentOption accountsservice.PaymentOption) accountsservice.PaymentOption {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paymentOption.Id = s.id(paymentOption.Id)
+	stamp(&paymentOption.Created, &paymentOption.Updated)
+	s.paymentOptions[paymentOption.Id] = &paymentOption
+
+	return paymentOption
+}
+
+// AddPlan stores plan under its brand slug and sku.
+func (s *Server) AddPlan(plan accountsservice.Plan) accountsservice.Plan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan.Id = s.id(plan.Id)
+	stamp(&plan.Created, &plan.Updated)
+	s.plans[plan.BrandSlug+"/"+plan.Sku] = &plan
+
+	return plan
+}
+
+// AddProduct stores product under its brand slug and sku.
+func (s *Server) AddProduct(product accountsservice.Product) accountsservice.Product {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product.Id = s.id(product.Id)
+	s.products[product.BrandSlug+"/"+product.Sku] = &product
+
+	return product
+}
+
+func (s *Server) Customer(id int) (customer accountsservice.Customer, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, found := s.customers[id]; found {
+		return *c, true
+	}
+	return
+}
+
+func (s *Server) Order(id int) (order accountsservice.Order, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o, found := s.orders[id]; found {
+		return *o, true
+	}
+	return
+}
+
+func (s *Server) Subscription(id int) (subscription accountsservice.Subscription, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sub, found := s.subscriptions[id]; found {
+		return *sub, true
+	}
+	return
+}
+
+func (s *Server) Transaction(id int) (transaction accountsservice.Transaction, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, found := s.transactions[id]; found {
+		return *t, true
+	}
+	return
+}
+
+func (s *Server) PaymentOption(id int) (paymentOption accountsservice.PaymentOption, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, found := s.paymentOptions[id]; found {
+		return *p, true
+	}
+	return
+}
+
+// ServeHTTP serves the accounts service api.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	w.Header().Set("X-Request-Id", strconv.Itoa(s.requests))
+
+	for i, fault := range s.faults {
+		if !fault.matches(r) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		for key, values := range fault.Header {
+			w.Header()[key] = values
+		}
+		writeError(w, fault.StatusCode, fault.Code, fault.Message, fault.Failures)
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+
+	if key == "" || r.Method == http.MethodGet {
+		s.route(w, r)
+		return
+	}
+
+	key = r.Method + " " + r.URL.Path + " " + key
+
+	if _, ok := s.idempotent[key]; !ok {
+		recorder := httptest.NewRecorder()
+		s.route(recorder, r)
+		s.idempotent[key] = response{recorder.Code, recorder.Header(), recorder.Body.Bytes()}
+	}
+
+	replay := s.idempotent[key]
+	for name, values := range replay.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(replay.status)
+	w.Write(replay.body)
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if len(parts) < 2 || parts[0] != "v1" {
+		writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path, nil)
+		return
+	}
+
+	resource, rest := parts[1], parts[2:]
+
+	var id int
+	if len(rest) > 0 && resource != "brands" {
+		var err error
+		if id, err = strconv.Atoi(rest[0]); err != nil {
+			writeError(w, http.StatusNotFound, "not_found", "Unknown path "+r.URL.Path, nil)
+			return
+		}
+	}
+
+	action := ""
+	if len(rest) > 1 {
+		action = rest[1]
+	}
+
+	switch {
+	case resource == "customers" && len(rest) == 0 && r.Method == http.MethodPost:
+		s.createCustomer(w, r)
+	case resource == "customers" && len(rest) == 1 && r.Method == http.MethodGet:
+		s.getCustomer(w, id)
+	case resource == "customers" && len(rest) == 1 && r.Method == http.MethodPatch:
+		s.updateCustomer(w, r, id)
+	case resource == "customers" && action == "orders" && r.Method == http.MethodGet:
+		s.list(w, r, filterOrders(s.orders, func(o *accountsservice.Order) bool { return o.CustomerId == id }))
+	case resource == "customers" && action == "payment_options" && r.Method == http.MethodGet:
+		s.list(w, r, filterPaymentOptions(s.paymentOptions, func(p *accountsservice.PaymentOption) bool { return p.CustomerId == id }))
+	case resource == "orders" && len(rest) == 0 && r.Method == http.MethodPost:
+		s.createOrder(w, r)
+	case resource == "orders" && len(rest) == 1 && r.Method == http.MethodGet:
+		getOne(w, s.orders, id, "Order")
+	case resource == "subscriptions" && len(rest) == 0 && r.Method == http.MethodGet:
+		s.list(w, r, values(s.subscriptions))
+	case resource == "subscriptions" && len(rest) == 1 && r.Method == http.MethodGet:
+		getOne(w, s.subscriptions, id, "Subscription")
+	case resource == "subscriptions" && action == "orders" && r.Method == http.MethodGet:
+		s.list(w, r, filterOrders(s.orders, func(o *accountsservice.Order) bool { return o.SubscriptionId != nil && *o.SubscriptionId == id }))
+	case resource == "subscriptions" && len(rest) == 2 && r.Method == http.MethodPost:
+		s.updateSubscription(w, r, id, action)
+	case resource == "transactions" && len(rest) == 0 && r.Method == http.MethodGet:
+		if r.URL.Query().Get("group") != "" {
+			s.aggregate(w, r)
+		} else {
+			s.list(w, r, values(s.transactions))
+		}
+	case resource == "transactions" && len(rest) == 2 && r.Method == http.MethodPost:
+		s.updateTransaction(w, r, id, action)
+	case resource == "payment_options" && len(rest) == 0 && r.Method == http.MethodGet:
+		s.list(w, r, values(s.paymentOptions))
+	case resource == "payment_options" && len(rest) == 0 && r.Method == http.MethodPost:
+		s.createPaymentOption(w, r)
+	case resource == "payment_options" && len(rest) == 1 && r.Method == http.MethodGet:
+		getOne(w, s.paymentOptions, id, "Payment option")
+	case resource == "payment_options" && len(rest) == 1 && r.Method == http.MethodPatch:
+		s.updatePaymentOption(w, r, id)
+	case resource == "payment_options" && action == "default" && r.Method == http.MethodPost:
+		s.setDefaultPaymentOption(w, id)
+	case resource == "brands" && len(rest) == 3 && rest[1] == "plans" && r.Method == http.MethodGet:
+		getOne(w, s.plans, rest[0]+"/"+rest[2], "Plan")
+	case resource == "brands" && len(rest) == 3 && rest[1] == "products" && r.Method == http.MethodGet:
+		getOne(w, s.products, rest[0]+"/"+rest[2], "Product")
+	default:
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("Unknown endpoint %s %s", r.Method, r.URL.Path), nil)
+	}
+}
+
+func getOne[K comparable, T any](w http.ResponseWriter, items map[K]*T, key K, name string) {
+	item, ok := items[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("%s %v not found", name, key), nil)
+		return
+	}
+	writeJson(w, http.StatusOK, item)
+}
+
+// values returns the items sorted by id.
+func values[T any](items map[int]*T) []interface{} {
+	ids := make([]int, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	result := make([]interface{}, len(ids))
+	for i, id := range ids {
+		result[i] = items[id]
+	}
+	return result
+}
+
+func filterOrders(orders map[int]*accountsservice.Order, keep func(*accountsservice.Order) bool) (result []interface{}) {
+	for _, item := range values(orders) {
+		if keep(item.(*accountsservice.Order)) {
+			result = append(result, item)
+		}
+	}
+	return
+}
+
+func filterPaymentOptions(paymentOptions map[int]*accountsservice.PaymentOption, keep func(*accountsservice.PaymentOption) bool) (result []interface{}) {
+	for _, item := range values(paymentOptions) {
+		if keep(item.(*accountsservice.PaymentOption)) {
+			result = append(result, item)
+		}
+	}
+	return
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, items []interface{}) {
+	q, err := parseQuery(r.URL.Query())
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error(), nil)
+		return
+	}
+
+	records := make([]record, len(items))
+	for i, item := range items {
+		records[i] = toRecord(item)
+	}
+
+	result, err := q.apply(records)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error(), nil)
+		return
+	}
+
+	if result == nil {
+		result = []record{}
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func (s *Server) aggregate(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	var expressions []string
+
+	if err := json.Unmarshal([]byte(values.Get("aggregate")), &expressions); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "aggregate must be a json array of strings", nil)
+		return
+	}
+
+	q, err := parseQuery(values)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error(), nil)
+		return
+	}
+
+	q.limit, q.offset, q.fields = 0, 0, nil
+
+	var records []record
+	for _, transaction := range s.transactions {
+		records = append(records, toRecord(transaction))
+	}
+
+	if records, err = q.apply(records); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error(), nil)
+		return
+	}
+
+	result, err := aggregate(records, values.Get("group"), expressions)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error(), nil)
+		return
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func (s *Server) getCustomer(w http.ResponseWriter, id int) {
+	getOne(w, s.customers, id, "Customer")
+}
+
+func (s *Server) createCustomer(w http.ResponseWriter, r *http.Request) {
+	var customer accountsservice.Customer
+
+	if !decode(w, r, &customer) {
+		return
+	}
+
+	if customer.Email == "" {
+		writeError(w, http.StatusUnprocessableEntity, "validation", "Invalid customer", []map[string]interface{}{
+			{"field": "email", "message": "is required"},
+		})
+		return
+	}
+
+	customer.Id = s.id(0)
+	customer.Created, customer.Updated = time.Time{}, time.Time{}
+	stamp(&customer.Created, &customer.Updated)
+	s.customers[customer.Id] = &customer
+
+	writeJson(w, http.StatusCreated, &customer)
+}
+
+func (s *Server) updateCustomer(w http.ResponseWriter, r *http.Request, id int) {
+	customer, ok := s.customers[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("Customer %d not found", id), nil)
+		return
+	}
+
+	updated := *customer
+
+	if !patch(w, r, &updated) {
+		return
+	}
+
+	updated.Id = id
+	updated.Updated = time.Now().UTC().Truncate(time.Second)
+	s.customers[id] = &updated
+
+	writeJson(w, http.StatusOK, &updated)
+}
+
+func (s *Server) updateSubscription(w http.ResponseWriter, r *http.Request, id int, action string) {
+	subscription, ok := s.subscriptions[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("Subscription %d not found", id), nil)
+		return
+	}
+
+	var body struct {
+		AtPeriodEnd bool   `json:"at_period_end"`
+		Reason      string `json:"reason"`
+		PlanSku     string `json:"plan_sku"`
+		Prorate     bool   `json:"prorate"`
+	}
+
+	if !decode(w, r, &body) {
+		return
+	}
+
+	updated := *subscription
+	now := time.Now().UTC().Truncate(time.Second)
+
+	switch action {
+	case "cancel":
+		if updated.Status == "canceled" {
+			writeError(w, http.StatusConflict, "conflict", "Subscription is already canceled", nil)
+			return
+		}
+		if body.AtPeriodEnd {
+			next := updated.Next
+			updated.Canceled = &next
+		} else {
+			updated.Status = "canceled"
+			updated.Canceled = &now
+		}
+	case "pause":
+		if updated.Status != "active" {
+			writeError(w, http.StatusConflict, "conflict", "Only active subscriptions can be paused", nil)
+			return
+		}
+		updated.Status = "paused"
+	case "resume":
+		if updated.Status != "paused" {
+			writeError(w, http.StatusConflict, "conflict", "Only paused subscriptions can be resumed", nil)
+			return
+		}
+		updated.Status = "active"
+	case "change_plan":
+		if _, ok := s.plans[updated.BrandSlug+"/"+body.PlanSku]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "validation", "Invalid plan", []map[string]interface{}{
+				{"field": "plan_sku", "message": "unknown plan " + body.PlanSku},
+			})
+			return
+		}
+		updated.PlanSku = body.PlanSku
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "Unknown subscription action "+action, nil)
+		return
+	}
+
+	updated.Updated = now
+	s.subscriptions[id] = &updated
+
+	writeJson(w, http.StatusOK, &updated)
+}
+
+func (s *Server) updateTransaction(w http.ResponseWriter, r *http.Request, id int, action string) {
+	transaction, ok := s.transactions[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("Transaction %d not found", id), nil)
+		return
+	}
+
+	var body struct {
+		Amount *currency.Amount `json:"amount"`
+		Reason string           `json:"reason"`
+	}
+
+	if !decode(w, r, &body) {
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	switch action {
+	case "refund":
+		amount := transaction.Amount
+		if body.Amount != nil {
+			amount = *body.Amount
+		}
+		if cents(amount) <= 0 || cents(amount) > cents(transaction.Amount) {
+			writeError(w, http.StatusUnprocessableEntity, "validation", "Invalid refund amount", []map[string]interface{}{
+				{"field": "amount", "message": "must be positive and at most " + transaction.Amount.String()},
+			})
+			return
+		}
+		refund := accountsservice.Transaction{
+			Id:               s.id(0),
+			BrandSlug:        transaction.BrandSlug,
+			OrderId:          transaction.OrderId,
+			CustomerId:       transaction.CustomerId,
+			Type:             "refund",
+			Status:           "approved",
+			Amount:           amount,
+			Created:          now,
+			Updated:          now,
+			PaymentProcessor: transaction.PaymentProcessor,
+			PaymentOptionId:  transaction.PaymentOptionId,
+		}
+		s.transactions[refund.Id] = &refund
+		writeJson(w, http.StatusCreated, &refund)
+	case "void":
+		if transaction.Status != "approved" && transaction.Status != "pending" {
+			writeError(w, http.StatusConflict, "conflict", "Only approved or pending transactions can be voided", nil)
+			return
+		}
+		updated := *transaction
+		updated.Status = "voided"
+		updated.Updated = now
+		s.transactions[id] = &updated
+		writeJson(w, http.StatusOK, &updated)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "Unknown transaction action "+action, nil)
+	}
+}
+
+func (s *Server) createPaymentOption(w http.ResponseWriter, r *http.Request) {
+	var body accountsservice.NewPaymentOption
+
+	if !decode(w, r, &body) {
+		return
+	}
+
+	if _, ok := s.customers[body.CustomerId]; !ok {
+		writeError(w, http.StatusUnprocessableEntity, "validation", "Invalid payment option", []map[string]interface{}{
+			{"field": "customer_id", "message": "unknown customer"},
+		})
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	paymentOption := accountsservice.PaymentOption{
+		Id:               s.id(0),
+		CustomerId:       body.CustomerId,
+		BrandSlug:        body.BrandSlug,
+		PaymentProcessor: body.PaymentProcessor,
+		Status:           "active",
+		Created:          now,
+		Updated:          now,
+	}
+
+	s.paymentOptions[paymentOption.Id] = &paymentOption
+
+	if body.Default {
+		s.makeDefault(paymentOption.Id)
+	}
+
+	writeJson(w, http.StatusCreated, s.paymentOptions[paymentOption.Id])
+}
+
+func (s *Server) updatePaymentOption(w http.ResponseWriter, r *http.Request, id int) {
+	paymentOption, ok := s.paymentOptions[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("Payment option %d not found", id), nil)
+		return
+	}
+
+	updated := *paymentOption
+
+	if !patch(w, r, &updated) {
+		return
+	}
+
+	updated.Id = id
+	updated.Updated = time.Now().UTC().Truncate(time.Second)
+	s.paymentOptions[id] = &updated
+
+	writeJson(w, http.StatusOK, &updated)
+}
+
+func (s *Server) setDefaultPaymentOption(w http.ResponseWriter, id int) {
+	paymentOption, ok := s.paymentOptions[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("Payment option %d not found", id), nil)
+		return
+	}
+
+	if paymentOption.Status != "active" {
+		writeError(w, http.StatusConflict, "conflict", "Only active payment options can be the default", nil)
+		return
+	}
+
+	s.makeDefault(id)
+
+	writeJson(w, http.StatusOK, s.paymentOptions[id])
+}
+
+// makeDefault makes a payment option the only default of its customer.
+func (s *Server) makeDefault(id int) {
+	customerId := s.paymentOptions[id].CustomerId
+
+	for otherId, other := range s.paymentOptions {
+		if other.CustomerId != customerId {
+			continue
+		}
+		updated := *other
+		updated.Default = otherId == id
+		s.paymentOptions[otherId] = &updated
+	}
+}
+
+func (s *Server) createOrder(w http.ResponseWriter, r *http.Request) {
+	var body accountsservice.NewOrder
+
+	if !decode(w, r, &body) {
+		return
+	}
+
+	var failures []map[string]interface{}
+
+	customer, ok := s.customers[body.CustomerId]
+	if !ok {
+		failures = append(failures, map[string]interface{}{"field": "customer_id", "message": "unknown customer"})
+	}
+
+	paymentOption, ok := s.paymentOptions[body.PaymentOptionId]
+	if !ok || paymentOption.CustomerId != body.CustomerId || paymentOption.Status != "active" {
+		failures = append(failures, map[string]interface{}{"field": "payment_option_id", "message": "unknown or inactive payment option"})
+	}
+
+	brandSlug := body.BrandSlug
+	if brandSlug == "" && customer != nil {
+		brandSlug = customer.BrandSlug
+	}
+
+	total := 0
+
+	for sku, quantity := range body.Plans {
+		plan, ok := s.plans[brandSlug+"/"+sku]
+		if !ok {
+			failures = append(failures, map[string]interface{}{"field": "plans", "message": "unknown plan " + sku})
+			continue
+		}
+		price := plan.RecurringPrice
+		if plan.TrialPeriod > 0 {
+			price = plan.TrialPrice
+		}
+		total += cents(price) * quantity.Quantity
+	}
+
+	for sku, quantity := range body.Products {
+		product, ok := s.products[brandSlug+"/"+sku]
+		if !ok {
+			failures = append(failures, map[string]interface{}{"field": "products", "message": "unknown product " + sku})
+			continue
+		}
+		total += cents(product.Price) * quantity.Quantity
+	}
+
+	if len(failures) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, "validation", "Invalid order", failures)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	amount := currency.Amount{Dollars: total / 100, Cents: total % 100}
+
+	order := accountsservice.Order{
+		Id:               s.id(0),
+		PaymentOptionid:  paymentOption.Id,
+		CustomerId:       body.CustomerId,
+		Type:             "initial",
+		Status:           "approved",
+		Amount:           amount,
+		Created:          now,
+		Updated:          now,
+		PaymentProcessor: paymentOption.PaymentProcessor,
+		BrandSlug:        brandSlug,
+		Begins:           now,
+		Ends:             now,
+		Plans:            body.Plans,
+		Products:         body.Products,
+	}
+	s.orders[order.Id] = &order
+
+	transaction := accountsservice.Transaction{
+		Id:               s.id(0),
+		BrandSlug:        brandSlug,
+		OrderId:          order.Id,
+		CustomerId:       order.CustomerId,
+		Type:             "sale",
+		Status:           "approved",
+		Amount:           amount,
+		Created:          now,
+		Updated:          now,
+		PaymentProcessor: paymentOption.PaymentProcessor,
+		PaymentOptionId:  paymentOption.Id,
+	}
+	s.transactions[transaction.Id] = &transaction
+
+	checkout := accountsservice.Checkout{
+		Order:         &order,
+		Transaction:   &transaction,
+		Subscriptions: []accountsservice.Subscription{},
+	}
+
+	skus := make([]string, 0, len(body.Plans))
+	for sku := range body.Plans {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	for _, sku := range skus {
+		plan := s.plans[brandSlug+"/"+sku]
+		subscription := accountsservice.Subscription{
+			Id:               s.id(0),
+			BrandSlug:        brandSlug,
+			OrderId:          order.Id,
+			Status:           "active",
+			Cycle:            1,
+			PlanSku:          sku,
+			PaymentProcessor: paymentOption.PaymentProcessor,
+			Created:          now,
+			Updated:          now,
+			CustomerId:       order.CustomerId,
+			Next:             next(now, plan),
+		}
+		s.subscriptions[subscription.Id] = &subscription
+		checkout.Subscriptions = append(checkout.Subscriptions, subscription)
+	}
+
+	writeJson(w, http.StatusCreated, &checkout)
+}
+
+// next returns when a subscription to plan started at now bills next.
+func next(now time.Time, plan *accountsservice.Plan) time.Time {
+	period, interval := plan.RecurringPeriod, plan.RecurringInterval
+	if plan.TrialPeriod > 0 {
+		period, interval = plan.TrialPeriod, plan.TrialInterval
+	}
+
+	switch strings.TrimSuffix(interval, "s") {
+	case "day":
+		return now.AddDate(0, 0, period)
+	case "week":
+		return now.AddDate(0, 0, 7*period)
+	case "year":
+		return now.AddDate(period, 0, 0)
+	}
+
+	return now.AddDate(0, period, 0)
+}
+
+func cents(amount currency.Amount) int {
+	return amount.Dollars*100 + amount.Cents
+}
+
+// decode json decodes the request body into v, answering 400 when it can't.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "Invalid json body: "+err.Error(), nil)
+		return false
+	}
+	return true
+}
+
+// patch merges the json object in the request body into v.
+func patch(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	data, err := ioutil.ReadAll(r.Body)
+
+	var changes map[string]interface{}
+
+	if err == nil {
+		err = json.Unmarshal(data, &changes)
+	}
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "Invalid json body", nil)
+		return false
+	}
+
+	merged := map[string]interface{}(toRecord(v))
+	merge(merged, changes)
+
+	data, _ = json.Marshal(merged)
+
+	if err = json.Unmarshal(data, v); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "validation", err.Error(), nil)
+		return false
+	}
+
+	return true
+}
+
+func merge(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			if dstMap, ok := dst[key].(map[string]interface{}); ok {
+				merge(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string, failures []map[string]interface{}) {
+	if code == "" {
+		code = strings.ToLower(strings.Replace(http.StatusText(status), " ", "_", -1))
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	writeJson(w, status, map[string]interface{}{
+		"error":    code,
+		"message":  message,
+		"failures": failures,
+	})
+}
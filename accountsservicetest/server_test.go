@@ -0,0 +1,178 @@
+package accountsservicetest
+
+// stdlib
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+)
+
+// send makes a request to s and returns the status and decoded body.
+func send(t *testing.T, s *Server, method, path, key, body string) (status int, v map[string]interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := ioutil.ReadAll(resp.Body)
+	json.Unmarshal(data, &v)
+
+	return resp.StatusCode, v
+}
+
+func TestFaults(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		status int
+	}
+
+	tests := []struct {
+		name     string
+		faults   []Fault
+		requests []request
+	}{
+		{
+			name:   "times",
+			faults: []Fault{{Path: "/v1/customers/1", StatusCode: http.StatusServiceUnavailable, Times: 2}},
+			requests: []request{
+				{"GET", "/v1/customers/1", http.StatusServiceUnavailable},
+				{"GET", "/v1/customers/2", http.StatusNotFound},
+				{"GET", "/v1/customers/1", http.StatusServiceUnavailable},
+				{"GET", "/v1/customers/1", http.StatusOK},
+			},
+		},
+		{
+			name:   "every request",
+			faults: []Fault{{}},
+			requests: []request{
+				{"GET", "/v1/customers/1", http.StatusInternalServerError},
+				{"PATCH", "/v1/customers/1", http.StatusInternalServerError},
+				{"GET", "/v1/customers/1", http.StatusInternalServerError},
+			},
+		},
+		{
+			name:   "method and path prefix",
+			faults: []Fault{{Method: "POST", Path: "/v1/subscriptions/*", StatusCode: http.StatusConflict}},
+			requests: []request{
+				{"GET", "/v1/subscriptions/1", http.StatusNotFound},
+				{"POST", "/v1/subscriptions/1/cancel", http.StatusConflict},
+				{"POST", "/v1/subscriptions", http.StatusNotFound},
+			},
+		},
+		{
+			name: "in order",
+			faults: []Fault{
+				{Path: "/v1/customers/1", StatusCode: http.StatusTooManyRequests, Times: 1},
+				{Path: "/v1/customers/*", StatusCode: http.StatusBadGateway, Times: 1},
+			},
+			requests: []request{
+				{"GET", "/v1/customers/1", http.StatusTooManyRequests},
+				{"GET", "/v1/customers/1", http.StatusBadGateway},
+				{"GET", "/v1/customers/1", http.StatusOK},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+
+			s.AddCustomer(accountsservice.Customer{Id: 1, Email: "ann@example.com"})
+
+			for _, fault := range test.faults {
+				s.InjectFault(fault)
+			}
+
+			for i, r := range test.requests {
+				if status, _ := send(t, s, r.method, r.path, "", "{}"); status != r.status {
+					t.Errorf("request %d %s %s: got %d, want %d", i, r.method, r.path, status, r.status)
+				}
+			}
+		})
+	}
+}
+
+func TestFaultResponse(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.InjectFault(Fault{
+		StatusCode: http.StatusUnprocessableEntity,
+		Code:       "validation",
+		Message:    "Invalid customer",
+		Failures:   []map[string]interface{}{{"field": "email", "message": "is required"}},
+		Header:     http.Header{"Retry-After": {"3"}},
+	})
+
+	_, err := s.Client().GetCustomer(1)
+
+	var apiError *accountsservice.APIError
+	if !accountsservice.IsValidation(err) || !errors.As(err, &apiError) {
+		t.Fatalf("got %v, want a validation error", err)
+	}
+
+	if apiError.Code != "validation" || apiError.Message != "Invalid customer" || len(apiError.Failures) != 1 || apiError.RequestId == "" {
+		t.Errorf("got %+v", apiError)
+	}
+
+	s.ClearFaults()
+
+	if _, err = s.Client().GetCustomer(1); !accountsservice.IsNotFound(err) {
+		t.Errorf("got %v after ClearFaults, want not found", err)
+	}
+}
+
+func TestIdempotencyKeyReplay(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	body := `{"email":"ann@example.com"}`
+
+	_, first := send(t, s, "POST", "/v1/customers", "key-1", body)
+	status, replayed := send(t, s, "POST", "/v1/customers", "key-1", body)
+	_, other := send(t, s, "POST", "/v1/customers", "key-2", body)
+	_, unkeyed := send(t, s, "POST", "/v1/customers", "", body)
+
+	if status != http.StatusCreated || replayed["id"] != first["id"] {
+		t.Errorf("replay got %d %v, want %v", status, replayed["id"], first["id"])
+	}
+
+	if other["id"] == first["id"] || unkeyed["id"] == other["id"] {
+		t.Errorf("got ids %v, %v and %v, want distinct customers", first["id"], other["id"], unkeyed["id"])
+	}
+
+	// Errors are replayed too.
+	status, _ = send(t, s, "POST", "/v1/customers", "key-3", `{}`)
+	replayedStatus, _ := send(t, s, "POST", "/v1/customers", "key-3", body)
+
+	if status != http.StatusUnprocessableEntity || replayedStatus != status {
+		t.Errorf("got %d then %d, want the validation error replayed", status, replayedStatus)
+	}
+
+	// The key is scoped to the method and path.
+	if status, _ = send(t, s, "PATCH", "/v1/customers/1", "key-1", `{"first_name":"Ann"}`); status != http.StatusOK {
+		t.Errorf("got %d for a different path with the same key, want 200", status)
+	}
+}
@@ -0,0 +1,228 @@
+package accountsservice
+
+// stdlib
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthProvider supplies the Authorization header sent with every request.
+type AuthProvider interface {
+	Authorization(ctx context.Context) (string, error)
+}
+
+// AuthInvalidator is implemented by AuthProviders whose credentials can go
+// stale. When the accounts service answers 401 the client calls Invalidate and
+// retries the request once with fresh credentials.
+type AuthInvalidator interface {
+	Invalidate()
+}
+
+// AuthProviderFunc adapts a function to an AuthProvider.
+type AuthProviderFunc func(ctx context.Context) (string, error)
+
+func (f AuthProviderFunc) Authorization(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithAuthProvider sets where the client gets its Authorization header from.
+func WithAuthProvider(provider AuthProvider) Option {
+	return func(c *Client) {
+		c.auth = provider
+	}
+}
+
+// StaticAuth always sends header as the Authorization header.
+func StaticAuth(header string) AuthProvider {
+	return AuthProviderFunc(func(ctx context.Context) (string, error) {
+		return header, nil
+	})
+}
+
+// FileTokenAuth sends the bearer token stored in a file, re-reading the file
+// whenever it changes so tokens can be rotated without a restart.
+type FileTokenAuth struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+func NewFileTokenAuth(path string) *FileTokenAuth {
+	return &FileTokenAuth{
+		path: path,
+	}
+}
+
+func (a *FileTokenAuth) Authorization(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, err := os.Stat(a.path)
+
+	if err != nil {
+		return "", err
+	}
+
+	if a.token == "" || !info.ModTime().Equal(a.modTime) || info.Size() != a.size {
+		data, err := ioutil.ReadFile(a.path)
+
+		if err != nil {
+			return "", err
+		}
+
+		a.token = strings.TrimSpace(string(data))
+		a.modTime = info.ModTime()
+		a.size = info.Size()
+	}
+
+	return "Bearer " + a.token, nil
+}
+
+// Invalidate forces the file to be read again.
+func (a *FileTokenAuth) Invalidate() {
+	a.mu.Lock()
+	a.token = ""
+	a.mu.Unlock()
+}
+
+type ClientCredentialsConfig struct {
+	TokenUrl     string
+	ClientId     string
+	ClientSecret string
+	Scopes       []string
+	// RefreshBefore is how long before expiry a token is refreshed, at most
+	// half its lifetime. Defaults to 1m.
+	RefreshBefore time.Duration
+	// HttpClient is used to fetch tokens. Defaults to a client with a 30s
+	// timeout.
+	HttpClient *http.Client
+}
+
+// ClientCredentialsAuth sends OAuth2 bearer tokens obtained with the client
+// credentials grant. Tokens are cached and refreshed shortly before they
+// expire.
+type ClientCredentialsAuth struct {
+	config ClientCredentialsConfig
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+func NewClientCredentialsAuth(config ClientCredentialsConfig) *ClientCredentialsAuth {
+	if config.RefreshBefore <= 0 {
+		config.RefreshBefore = time.Minute
+	}
+	if config.HttpClient == nil {
+		config.HttpClient = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+
+	return &ClientCredentialsAuth{
+		config: config,
+	}
+}
+
+func (a *ClientCredentialsAuth) Authorization(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || (!a.refreshAt.IsZero() && time.Now().After(a.refreshAt)) {
+		if err := a.refresh(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return "Bearer " + a.token, nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one.
+func (a *ClientCredentialsAuth) Invalidate() {
+	a.mu.Lock()
+	a.token = ""
+	a.mu.Unlock()
+}
+
+func (a *ClientCredentialsAuth) refresh(ctx context.Context) (err error) {
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+
+	if len(a.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.config.Scopes, " "))
+	}
+
+	var req *http.Request
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, a.config.TokenUrl, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.config.ClientId), url.QueryEscape(a.config.ClientSecret))
+
+	var resp *http.Response
+
+	resp, err = a.config.HttpClient.Do(req)
+
+	if err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+
+	var body []byte
+	body, err = readBody(resp.Body)
+
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("accountsservice: token request failed with %d: %s", resp.StatusCode, snippet(bytes.TrimSpace(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err = json.Unmarshal(body, &token); err != nil {
+		return
+	}
+
+	if token.AccessToken == "" {
+		return fmt.Errorf("accountsservice: token response has no access_token")
+	}
+
+	a.token = token.AccessToken
+	a.refreshAt = time.Time{}
+
+	if token.ExpiresIn > 0 {
+		lifetime := time.Duration(token.ExpiresIn) * time.Second
+		margin := a.config.RefreshBefore
+		if margin > lifetime/2 {
+			margin = lifetime / 2
+		}
+		a.refreshAt = time.Now().Add(lifetime - margin)
+	}
+
+	return
+}
@@ -0,0 +1,200 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTokenServer returns an OAuth2 token endpoint handing out token-1,
+// token-2, ... valid for expiresIn seconds, counting the tokens issued.
+func newTokenServer(t *testing.T, expiresIn int, issued *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, _ := r.BasicAuth()
+
+		if r.FormValue("grant_type") != "client_credentials" || clientId != "id" || clientSecret != "secret" {
+			t.Errorf("got grant %q for %s:%s", r.FormValue("grant_type"), clientId, clientSecret)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if scope := r.FormValue("scope"); scope != "read write" {
+			t.Errorf("got scope %q, want read write", scope)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, atomic.AddInt32(issued, 1), expiresIn)
+	}))
+}
+
+func TestClientCredentialsAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn int
+		wait      time.Duration
+		tokens    []string
+	}{
+		{"caches tokens", 3600, 0, []string{"Bearer token-1", "Bearer token-1"}},
+		{"caches tokens shorter lived than RefreshBefore", 30, 0, []string{"Bearer token-1", "Bearer token-1"}},
+		{"refreshes after half a short lifetime", 1, 600 * time.Millisecond, []string{"Bearer token-1", "Bearer token-2"}},
+		{"never expires", 0, 0, []string{"Bearer token-1", "Bearer token-1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var issued int32
+
+			server := newTokenServer(t, test.expiresIn, &issued)
+			defer server.Close()
+
+			auth := NewClientCredentialsAuth(ClientCredentialsConfig{
+				TokenUrl:     server.URL,
+				ClientId:     "id",
+				ClientSecret: "secret",
+				Scopes:       []string{"read", "write"},
+			})
+
+			for i, want := range test.tokens {
+				if i > 0 {
+					time.Sleep(test.wait)
+				}
+
+				if got, err := auth.Authorization(context.Background()); err != nil || got != want {
+					t.Fatalf("call %d: got %q, %v, want %q", i, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClientCredentialsAuthErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer server.Close()
+
+	auth := NewClientCredentialsAuth(ClientCredentialsConfig{TokenUrl: server.URL})
+
+	_, err := auth.Authorization(context.Background())
+
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("got %v, want the token request's status", err)
+	}
+
+	var requests int32
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer api.Close()
+
+	if _, err = NewClient(WithBaseUrl(api.URL), WithAuthProvider(auth)).GetCustomer(1); err == nil || requests != 0 {
+		t.Fatalf("got %v after %d requests, want the token error before any request", err, requests)
+	}
+}
+
+func TestClientRefreshesTokenOn401(t *testing.T) {
+	tests := []struct {
+		name     string
+		valid    string
+		status   int
+		issued   int32
+		requests int32
+	}{
+		{"retries with a new token", "Bearer token-2", http.StatusOK, 2, 2},
+		{"retries once", "Bearer token-3", http.StatusUnauthorized, 2, 2},
+		{"keeps a valid token", "Bearer token-1", http.StatusOK, 1, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var issued, requests int32
+
+			tokens := newTokenServer(t, 3600, &issued)
+			defer tokens.Close()
+
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.Header().Set("Content-Type", "application/json")
+
+				if body, _ := ioutil.ReadAll(r.Body); string(body) != `{"email":"new@example.com"}` {
+					t.Errorf("got body %s", body)
+				}
+
+				if r.Header.Get("Authorization") != test.valid {
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"error":"unauthorized"}`))
+					return
+				}
+
+				w.Write([]byte(`{"id":1,"email":"new@example.com"}`))
+			}))
+			defer api.Close()
+
+			client := NewClient(
+				WithBaseUrl(api.URL),
+				WithAuthProvider(NewClientCredentialsAuth(ClientCredentialsConfig{
+					TokenUrl:     tokens.URL,
+					ClientId:     "id",
+					ClientSecret: "secret",
+					Scopes:       []string{"read", "write"},
+				})),
+			)
+
+			email := "new@example.com"
+			_, err := client.UpdateCustomer(1, &CustomerUpdate{Email: &email})
+
+			var apiError *APIError
+			switch {
+			case test.status == http.StatusOK && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case test.status != http.StatusOK && (!errors.As(err, &apiError) || apiError.StatusCode != test.status):
+				t.Fatalf("got error %v, want status %d", err, test.status)
+			}
+
+			if issued != test.issued || requests != test.requests {
+				t.Errorf("got %d tokens and %d requests, want %d and %d", issued, requests, test.issued, test.requests)
+			}
+		})
+	}
+}
+
+func TestFileTokenAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+
+	if err := ioutil.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth := NewFileTokenAuth(path)
+
+	if got, err := auth.Authorization(context.Background()); err != nil || got != "Bearer first" {
+		t.Fatalf("got %q, %v, want Bearer first", got, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("rotated\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+
+	if got, err := auth.Authorization(context.Background()); err != nil || got != "Bearer rotated" {
+		t.Fatalf("got %q, %v, want Bearer rotated", got, err)
+	}
+
+	os.Remove(path)
+
+	if _, err := auth.Authorization(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want os.ErrNotExist", err)
+	}
+}
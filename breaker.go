@@ -0,0 +1,247 @@
+package accountsservice
+
+// stdlib
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the accounts service while the
+// client's circuit breaker is open.
+var ErrCircuitOpen = errors.New("accountsservice: circuit breaker is open")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker. Zero fields take the
+// defaults noted below.
+type CircuitBreakerConfig struct {
+	// FailureRatio of failed requests within Window that trips the breaker.
+	// Defaults to 0.5.
+	FailureRatio float64
+	// MinRequests is the number of requests within Window required before the
+	// breaker can trip. Defaults to 10.
+	MinRequests int
+	// Window is the period over which failures are counted. Defaults to 1m.
+	Window time.Duration
+	// CoolDown is how long the breaker stays open before letting probe
+	// requests through. Defaults to 30s.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of probe requests that must succeed to
+	// close the breaker again. Defaults to 1.
+	HalfOpenRequests int
+	// IsFailure decides whether a request counts as a failure. By default
+	// errors and 5xx responses are failures.
+	IsFailure func(resp *http.Response, err error) bool
+	// OnStateChange is called after every state transition.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker fails requests fast with ErrCircuitOpen once too many of them
+// failed, giving a degraded accounts service time to recover. A CircuitBreaker
+// may be shared by several clients.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  int
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureRatio <= 0 {
+		config.FailureRatio = 0.5
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 10
+	}
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode >= 500
+		}
+	}
+
+	return &CircuitBreaker{
+		config:      config,
+		windowStart: time.Now(),
+	}
+}
+
+// WithCircuitBreaker routes every request through breaker.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *Client) {
+		c.breaker = breaker
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+
+	from := b.state
+	b.refresh(time.Now())
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+
+	return to
+}
+
+// allow reports whether a request may be sent. The returned generation must
+// be passed to record once the request completes.
+func (b *CircuitBreaker) allow() (generation int, err error) {
+	b.mu.Lock()
+
+	from := b.state
+	b.refresh(time.Now())
+
+	switch b.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probes >= b.config.HalfOpenRequests {
+			err = ErrCircuitOpen
+		} else {
+			b.probes++
+		}
+	default:
+		b.requests++
+	}
+
+	generation = b.generation
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+
+	return
+}
+
+// record registers the outcome of a request allowed in generation. Outcomes
+// from an earlier generation are ignored.
+func (b *CircuitBreaker) record(generation int, resp *http.Response, err error) {
+	failure := b.config.IsFailure(resp, err)
+
+	b.mu.Lock()
+
+	from := b.state
+
+	if generation == b.generation {
+		now := time.Now()
+
+		switch b.state {
+		case CircuitClosed:
+			if failure {
+				b.failures++
+			}
+			if b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.FailureRatio {
+				b.setState(CircuitOpen, now)
+			}
+		case CircuitHalfOpen:
+			if failure {
+				b.setState(CircuitOpen, now)
+			} else {
+				b.successes++
+				if b.successes >= b.config.HalfOpenRequests {
+					b.setState(CircuitClosed, now)
+				}
+			}
+		}
+	}
+
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// forget undoes allow for a request whose outcome says nothing about the
+// health of the service, e.g. because its context was canceled.
+func (b *CircuitBreaker) forget(generation int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		b.requests--
+	case CircuitHalfOpen:
+		b.probes--
+	}
+}
+
+// refresh moves an open breaker to half-open once the cool down passed and
+// starts a new counting window when the current one expired.
+func (b *CircuitBreaker) refresh(now time.Time) {
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) >= b.config.CoolDown {
+			b.setState(CircuitHalfOpen, now)
+		}
+	case CircuitClosed:
+		if now.Sub(b.windowStart) >= b.config.Window {
+			b.generation++
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+	}
+}
+
+func (b *CircuitBreaker) setState(state CircuitState, now time.Time) {
+	b.state = state
+	b.generation++
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+	b.probes = 0
+	b.successes = 0
+
+	if state == CircuitOpen {
+		b.openedAt = now
+	}
+}
+
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,233 @@
+package accountsservice
+
+// stdlib
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	okResponse = &http.Response{StatusCode: http.StatusOK}
+	errFailure = errors.New("connection refused")
+)
+
+// request runs a request through b, failing it when fail is true.
+func request(t *testing.T, b *CircuitBreaker, fail bool) {
+	t.Helper()
+
+	generation, err := b.allow()
+	if err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+
+	if fail {
+		b.record(generation, nil, errFailure)
+	} else {
+		b.record(generation, okResponse, nil)
+	}
+}
+
+func TestCircuitBreakerTrips(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 4, CoolDown: time.Hour})
+
+	request(t, b, true)
+	request(t, b, true)
+	request(t, b, true)
+
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("tripped before MinRequests: %v", state)
+	}
+
+	request(t, b, false)
+
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("got %v, want open", state)
+	}
+
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerStaysClosedBelowRatio(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 4, FailureRatio: 0.5})
+
+	for i := 0; i < 20; i++ {
+		request(t, b, i%4 == 1)
+	}
+
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("got %v, want closed", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		probes []bool
+		want   CircuitState
+	}{
+		{"closes after successful probes", []bool{false, false}, CircuitClosed},
+		{"reopens on failed probe", []bool{false, true}, CircuitOpen},
+		{"waits for every probe", []bool{false}, CircuitHalfOpen},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := NewCircuitBreaker(CircuitBreakerConfig{
+				MinRequests:      1,
+				CoolDown:         50 * time.Millisecond,
+				HalfOpenRequests: 2,
+			})
+
+			request(t, b, true)
+			time.Sleep(60 * time.Millisecond)
+
+			if state := b.State(); state != CircuitHalfOpen {
+				t.Fatalf("got %v after cool down, want half-open", state)
+			}
+
+			for _, fail := range test.probes {
+				request(t, b, fail)
+			}
+
+			if state := b.State(); state != test.want {
+				t.Fatalf("got %v, want %v", state, test.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerLimitsProbes(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 1, CoolDown: 10 * time.Millisecond})
+
+	request(t, b, true)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := b.allow(); err != nil {
+		t.Fatalf("probe not allowed: %v", err)
+	}
+
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe: got %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleGenerations(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 1, CoolDown: 10 * time.Millisecond})
+
+	stale, _ := b.allow()
+
+	request(t, b, true)
+	time.Sleep(20 * time.Millisecond)
+
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("got %v, want half-open", state)
+	}
+
+	// A success started before the breaker opened must not close it.
+	b.record(stale, okResponse, nil)
+
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("stale success moved breaker to %v", state)
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 4, Window: 20 * time.Millisecond})
+
+	stale, _ := b.allow()
+	request(t, b, true)
+	request(t, b, true)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// The new window starts empty and ignores outcomes from the old one, so
+	// this is 1 failure in 4 requests rather than 2.
+	request(t, b, false)
+	request(t, b, false)
+	request(t, b, false)
+	b.record(stale, nil, errFailure)
+	request(t, b, true)
+
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("got %v, want closed", state)
+	}
+}
+
+func TestCircuitBreakerForget(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 2})
+
+	generation, _ := b.allow()
+	b.forget(generation)
+
+	request(t, b, true)
+
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("forgotten request counted, breaker is %v", state)
+	}
+}
+
+func TestCircuitBreakerOnStateChange(t *testing.T) {
+	var b *CircuitBreaker
+	var transitions []string
+
+	b = NewCircuitBreaker(CircuitBreakerConfig{
+		MinRequests: 1,
+		CoolDown:    10 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			// Calling back into the breaker must not deadlock.
+			b.State()
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	request(t, b, true)
+	time.Sleep(20 * time.Millisecond)
+	request(t, b, false)
+
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+
+	if len(transitions) != len(want) {
+		t.Fatalf("got transitions %v, want %v", transitions, want)
+	}
+
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("got transitions %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseUrl(server.URL),
+		WithCircuitBreaker(NewCircuitBreaker(CircuitBreakerConfig{MinRequests: 3, CoolDown: time.Hour})),
+	)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetCustomer(1); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: breaker open too early", i)
+		}
+	}
+
+	if _, err := client.GetCustomer(1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+}
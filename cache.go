@@ -0,0 +1,311 @@
+package accountsservice
+
+// stdlib
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTLs caches plans and products, which rarely change, for an
+// hour.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"plans":    time.Hour,
+	"products": time.Hour,
+}
+
+// CacheConfig configures a Cache. Zero fields take the defaults noted below.
+type CacheConfig struct {
+	// TTLs maps resources, e.g. plans, products or customers, to how long
+	// their responses are cached. Defaults to DefaultCacheTTLs.
+	TTLs map[string]time.Duration
+	// TTL applies to resources missing from TTLs. Defaults to 0, which
+	// doesn't cache them.
+	TTL time.Duration
+	// MaxEntries is the number of responses kept before the least recently
+	// used ones are evicted. Defaults to 1000.
+	MaxEntries int
+	// MaxBytes caps the total size of the cached response bodies. Defaults to
+	// 0, no cap.
+	MaxBytes int
+}
+
+// CacheStats counts the lookups and evictions of a Cache.
+type CacheStats struct {
+	Hits        int
+	Misses      int
+	Evictions   int
+	Expirations int
+	Entries     int
+	Bytes       int
+}
+
+// Cache is an in-memory LRU cache of successful GET responses, keyed by
+// request url and credentials. A Cache may be shared by several clients, each
+// of which only sees the responses fetched with its own Authorization, so a
+// refreshed token starts with none cached. A successful write made through any
+// of them evicts every cached response of the resource it touches, including
+// lists nested under other resources, e.g. /v1/customers/1/orders after
+// CreateOrder, and lists of the resources it creates as a side effect. Changes
+// made by other clients or by the accounts service itself are seen once the
+// responses expire.
+type Cache struct {
+	config CacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	stats   CacheStats
+	// generation counts invalidations, so that responses fetched before one
+	// aren't stored after it.
+	generation uint64
+}
+
+type cacheEntry struct {
+	key      string
+	resource string
+	id       string
+	nested   string
+	data     []byte
+	expires  time.Time
+}
+
+func NewCache(config CacheConfig) *Cache {
+	if config.TTLs == nil {
+		config.TTLs = DefaultCacheTTLs
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+
+	return &Cache{
+		config:  config,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// WithCache caches GET responses in cache.
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// Stats returns the cache's counters.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+
+	return stats
+}
+
+// Invalidate removes the cached responses of a resource, e.g. customers and
+// 1 for /v1/customers/1 and /v1/customers/1/orders. Plans and products are
+// identified by brand slug and sku, e.g. plans and brand/sku. An empty id
+// removes every response of the resource.
+func (c *Cache) Invalidate(resource, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeIf(func(entry *cacheEntry) bool {
+		return entry.resource == resource && (id == "" || entry.id == id)
+	})
+}
+
+func (c *Cache) InvalidatePlan(brandSlug, sku string) {
+	c.Invalidate("plans", brandSlug+"/"+sku)
+}
+
+func (c *Cache) InvalidateProduct(brandSlug, sku string) {
+	c.Invalidate("products", brandSlug+"/"+sku)
+}
+
+// Purge removes every cached response. The stats are kept.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+	c.stats.Bytes = 0
+	c.generation++
+}
+
+// ttl returns how long responses for path are cached.
+func (c *Cache) ttl(path string) time.Duration {
+	resource, _, _ := cacheResource(path)
+
+	if ttl, ok := c.config.TTLs[resource]; ok {
+		return ttl
+	}
+
+	return c.config.TTL
+}
+
+// get returns the data cached under key. On a miss it returns the cache's
+// generation to pass to set.
+func (c *Cache) get(key string) (data []byte, generation uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generation = c.generation
+
+	element, found := c.entries[key]
+
+	if !found {
+		c.stats.Misses++
+		return
+	}
+
+	entry := element.Value.(*cacheEntry)
+
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return
+	}
+
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+
+	return entry.data, generation, true
+}
+
+// set stores data under key unless the cache was invalidated since generation,
+// returned by the get that missed, as data may predate the invalidation.
+func (c *Cache) set(key, path string, data []byte, ttl time.Duration, generation uint64) {
+	if c.config.MaxBytes > 0 && len(data) > c.config.MaxBytes {
+		return
+	}
+
+	resource, id, nested := cacheResource(path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:      key,
+		resource: resource,
+		id:       id,
+		nested:   nested,
+		data:     data,
+		expires:  time.Now().Add(ttl),
+	})
+	c.stats.Bytes += len(data)
+
+	for c.order.Len() > c.config.MaxEntries || c.config.MaxBytes > 0 && c.stats.Bytes > c.config.MaxBytes {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// writeEffects lists the resources a write creates besides the one it is
+// made to, e.g. checking out an order creates a transaction and subscriptions.
+var writeEffects = map[string][]string{
+	"orders":       {"transactions", "subscriptions"},
+	"transactions": {"orders"},
+}
+
+// invalidate removes the responses a write to path may have changed: those of
+// its resource and every list of that resource or the ones it creates.
+func (c *Cache) invalidate(path string) {
+	resource, _, _ := cacheResource(path)
+	effects := writeEffects[resource]
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeIf(func(entry *cacheEntry) bool {
+		if entry.resource == resource || entry.nested == resource {
+			return true
+		}
+		for _, effect := range effects {
+			if entry.nested == effect || entry.resource == effect && entry.id == "" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (c *Cache) removeIf(match func(*cacheEntry) bool) {
+	c.generation++
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if match(element.Value.(*cacheEntry)) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.stats.Bytes -= len(entry.data)
+}
+
+// cacheKey identifies the response to a GET of path by the client's url and
+// credentials.
+func (c *Client) cacheKey(ctx context.Context, path string) (key string, err error) {
+	var authorization string
+
+	if c.auth != nil {
+		if authorization, err = c.auth.Authorization(ctx); err != nil {
+			return
+		}
+	}
+
+	sum := sha256.Sum256([]byte(authorization))
+	key = hex.EncodeToString(sum[:8]) + " " + c.baseUrl + path
+
+	return
+}
+
+// cacheResource returns the resource, id and nested resource path refers to,
+// e.g. customers, 1 and orders for /v1/customers/1/orders, or plans and
+// brand/sku for /v1/brands/brand/plans/sku.
+func cacheResource(path string) (resource, id, nested string) {
+	path = strings.SplitN(path, "?", 2)[0]
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(parts) > 0 && parts[0] == "v1" {
+		parts = parts[1:]
+	}
+
+	if len(parts) >= 4 && parts[0] == "brands" {
+		return parts[2], parts[1] + "/" + parts[3], ""
+	}
+
+	if len(parts) > 0 {
+		resource = parts[0]
+	}
+
+	if len(parts) > 1 {
+		id = parts[1]
+	}
+
+	if len(parts) > 2 {
+		nested = parts[2]
+	}
+
+	return
+}
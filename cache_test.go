@@ -0,0 +1,293 @@
+package accountsservice_test
+
+// stdlib
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+	"github.com/the-control-group/go-accounts-service-client/accountsservicetest"
+)
+
+// newCachingServer returns a server with a customer, a payment option and
+// plans a, b and c of brand, and a cache.
+func newCachingServer(config accountsservice.CacheConfig) (server *accountsservicetest.Server, customer accountsservice.Customer, paymentOption accountsservice.PaymentOption, cache *accountsservice.Cache) {
+	server = accountsservicetest.NewServer()
+
+	customer = server.AddCustomer(accountsservice.Customer{BrandSlug: "brand"})
+	paymentOption = server.AddPaymentOption(accountsservice.PaymentOption{CustomerId: customer.Id, Status: "active"})
+
+	for _, sku := range []string{"a", "b", "c"} {
+		server.AddPlan(accountsservice.Plan{BrandSlug: "brand", Sku: sku, RecurringPeriod: 1, RecurringInterval: "month"})
+	}
+
+	cache = accountsservice.NewCache(config)
+
+	return
+}
+
+func TestCacheHits(t *testing.T) {
+	server, _, _, cache := newCachingServer(accountsservice.CacheConfig{})
+	defer server.Close()
+
+	var requests int32
+	client := server.Client(accountsservice.WithCache(cache), accountsservice.WithMiddleware(counting(&requests)))
+
+	for i := 0; i < 3; i++ {
+		if plan, err := client.GetPlan("brand", "a"); err != nil || plan.Sku != "a" {
+			t.Fatalf("got %v, %v, want plan a", plan, err)
+		}
+	}
+
+	// Errors aren't cached.
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPlan("brand", "missing"); !errors.Is(err, accountsservice.ErrNotFound) {
+			t.Fatalf("got %v, want ErrNotFound", err)
+		}
+	}
+
+	// Customers have no TTL by default.
+	client.GetCustomer(1)
+	client.GetCustomer(1)
+
+	if requests != 5 {
+		t.Errorf("got %d requests, want 5", requests)
+	}
+
+	stats := cache.Stats()
+
+	if stats.Hits != 2 || stats.Misses != 3 || stats.Entries != 1 || stats.Bytes == 0 {
+		t.Errorf("got stats %+v, want 2 hits, 3 misses and 1 entry", stats)
+	}
+}
+
+func TestCacheInvalidatesWrites(t *testing.T) {
+	server, customer, paymentOption, cache := newCachingServer(accountsservice.CacheConfig{TTL: time.Hour})
+	defer server.Close()
+
+	client := server.Client(accountsservice.WithCache(cache))
+
+	if orders, err := client.GetCustomerOrders(customer.Id); err != nil || len(orders) != 0 {
+		t.Fatalf("got %v, %v, want no orders", orders, err)
+	}
+
+	if transactions, err := client.GetTransactions(nil); err != nil || len(transactions) != 0 {
+		t.Fatalf("got %v, %v, want no transactions", transactions, err)
+	}
+
+	checkout, err := client.CreateOrder(&accountsservice.NewOrder{
+		CustomerId:      customer.Id,
+		PaymentOptionId: paymentOption.Id,
+		Plans:           map[string]accountsservice.OrderQuantity{"a": {Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if orders, err := client.GetCustomerOrders(customer.Id); err != nil || len(orders) != 1 {
+		t.Fatalf("got %v, %v after CreateOrder, want 1 order", orders, err)
+	}
+
+	if transactions, err := client.GetTransactions(nil); err != nil || len(transactions) != 1 {
+		t.Fatalf("got %v, %v after CreateOrder, want 1 transaction", transactions, err)
+	}
+
+	subscriptions, err := client.GetOrderSubscriptions(checkout.Order.Id)
+	if err != nil || len(subscriptions) != 1 || subscriptions[0].Status != "active" {
+		t.Fatalf("got %v, %v, want an active subscription", subscriptions, err)
+	}
+
+	if _, err = client.CancelSubscription(subscriptions[0].Id, false, "too expensive"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	subscriptions, err = client.GetOrderSubscriptions(checkout.Order.Id)
+	if err != nil || len(subscriptions) != 1 || subscriptions[0].Status != "canceled" {
+		t.Fatalf("got %v, %v after CancelSubscription, want a canceled subscription", subscriptions, err)
+	}
+
+	// Writes leave unrelated resources cached.
+	client.GetPlan("brand", "a")
+	hits := cache.Stats().Hits
+
+	email := "new@example.com"
+	if _, err = client.UpdateCustomer(customer.Id, &accountsservice.CustomerUpdate{Email: &email}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client.GetPlan("brand", "a")
+
+	if cache.Stats().Hits != hits+1 {
+		t.Errorf("plan evicted by a customer write")
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	server, _, _, cache := newCachingServer(accountsservice.CacheConfig{})
+	defer server.Close()
+
+	var requests int32
+	client := server.Client(accountsservice.WithCache(cache), accountsservice.WithMiddleware(counting(&requests)))
+
+	client.GetPlan("brand", "a")
+	client.GetPlan("brand", "b")
+
+	cache.InvalidatePlan("brand", "a")
+
+	client.GetPlan("brand", "a")
+	client.GetPlan("brand", "b")
+
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+
+	cache.Purge()
+
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("got stats %+v after Purge, want no entries", stats)
+	}
+}
+
+func TestCacheLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   accountsservice.CacheConfig
+		wait     time.Duration
+		requests int32
+		stats    accountsservice.CacheStats
+	}{
+		{
+			name:     "max entries",
+			config:   accountsservice.CacheConfig{MaxEntries: 2},
+			requests: 4,
+			stats:    accountsservice.CacheStats{Misses: 4, Evictions: 2, Entries: 2},
+		},
+		{
+			name:     "max bytes",
+			config:   accountsservice.CacheConfig{MaxBytes: 10},
+			requests: 4,
+			stats:    accountsservice.CacheStats{Misses: 4},
+		},
+		{
+			name:     "expiry",
+			config:   accountsservice.CacheConfig{TTLs: map[string]time.Duration{"plans": 20 * time.Millisecond}},
+			wait:     30 * time.Millisecond,
+			requests: 4,
+			stats:    accountsservice.CacheStats{Misses: 4, Expirations: 1, Entries: 3},
+		},
+		{
+			name:     "within limits",
+			config:   accountsservice.CacheConfig{},
+			wait:     10 * time.Millisecond,
+			requests: 3,
+			stats:    accountsservice.CacheStats{Hits: 1, Misses: 3, Entries: 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, _, _, cache := newCachingServer(test.config)
+			defer server.Close()
+
+			var requests int32
+			client := server.Client(accountsservice.WithCache(cache), accountsservice.WithMiddleware(counting(&requests)))
+
+			client.GetPlan("brand", "a")
+			time.Sleep(test.wait)
+			client.GetPlan("brand", "b")
+			client.GetPlan("brand", "c")
+			client.GetPlan("brand", "a")
+
+			if requests != test.requests {
+				t.Errorf("got %d requests, want %d", requests, test.requests)
+			}
+
+			stats := cache.Stats()
+			stats.Bytes = 0
+
+			if stats != test.stats {
+				t.Errorf("got stats %+v, want %+v", stats, test.stats)
+			}
+		})
+	}
+}
+
+func TestCacheCredentials(t *testing.T) {
+	server, _, _, cache := newCachingServer(accountsservice.CacheConfig{})
+	defer server.Close()
+
+	var requests int32
+
+	for _, authorization := range []string{"Bearer brand", "Bearer other", "Bearer brand", ""} {
+		client := server.Client(
+			accountsservice.WithCache(cache),
+			accountsservice.WithAuthorization(authorization),
+			accountsservice.WithMiddleware(counting(&requests)),
+		)
+
+		if _, err := client.GetPlan("brand", "a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests != 3 {
+		t.Errorf("got %d requests, want one per credential", requests)
+	}
+}
+
+func TestCacheSkipsResponsesFetchedBeforeAWrite(t *testing.T) {
+	server, customer, paymentOption, cache := newCachingServer(accountsservice.CacheConfig{TTL: time.Hour})
+	defer server.Close()
+
+	checkout, err := server.Client().CreateOrder(&accountsservice.NewOrder{
+		CustomerId:      customer.Id,
+		PaymentOptionId: paymentOption.Id,
+		Plans:           map[string]accountsservice.OrderQuantity{"a": {Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fetched, release := make(chan struct{}), make(chan struct{})
+	var held int32
+
+	// The first GET is held after its response arrived, until a write is done.
+	hold := func(next http.RoundTripper) http.RoundTripper {
+		return accountsservice.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if req.Method == http.MethodGet && atomic.CompareAndSwapInt32(&held, 0, 1) {
+				close(fetched)
+				<-release
+			}
+			return resp, err
+		})
+	}
+
+	client := server.Client(accountsservice.WithCache(cache), accountsservice.WithMiddleware(hold))
+
+	done := make(chan struct{})
+	go func() {
+		client.GetOrderSubscriptions(checkout.Order.Id)
+		close(done)
+	}()
+
+	<-fetched
+
+	if _, err = client.CancelSubscription(checkout.Subscriptions[0].Id, false, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	close(release)
+	<-done
+
+	subscriptions, err := client.GetOrderSubscriptions(checkout.Order.Id)
+	if err != nil || len(subscriptions) != 1 || subscriptions[0].Status != "canceled" {
+		t.Fatalf("got %v, %v, want the canceled subscription rather than the response fetched before canceling", subscriptions, err)
+	}
+}
@@ -2,404 +2,395 @@ package accountsservice
 
 // stdlib
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
 var ACCOUNTS_SERVICE_TIMEOUT = os.Getenv("ACCOUNTS_SERVICE_TIMEOUT")
 var ACCOUNTS_SERVICE_API_URL = os.Getenv("ACCOUNTS_SERVICE_API_URL")
-var AUTHORIZATION_HEADER     = os.Getenv("AUTHORIZATION_HEADER")
+var AUTHORIZATION_HEADER = os.Getenv("AUTHORIZATION_HEADER")
 
-var client *http.Client
+// DefaultClient is used by the package level functions. It is configured from
+// the ACCOUNTS_SERVICE_* and AUTHORIZATION_HEADER environment variables, and
+// reconfigured by the next package level call whenever ACCOUNTS_SERVICE_API_URL,
+// AUTHORIZATION_HEADER or ACCOUNTS_SERVICE_TIMEOUT are changed.
+var DefaultClient *Client
+
+var (
+	defaultMu       sync.Mutex
+	defaultSettings [3]string
+)
 
 func init() {
 	if ACCOUNTS_SERVICE_TIMEOUT == "" {
 		ACCOUNTS_SERVICE_TIMEOUT = "30s"
-		log.Println("Using default accounts service timeout", ACCOUNTS_SERVICE_TIMEOUT)
-	}
-	timeout, err := time.ParseDuration(ACCOUNTS_SERVICE_TIMEOUT)
-	if err != nil {
-	}
-	client = &http.Client{
-		Timeout: timeout,
 	}
 	if ACCOUNTS_SERVICE_API_URL == "" {
 		ACCOUNTS_SERVICE_API_URL = "http://localhost:8000"
 	}
+	DefaultClient = NewClient()
+	defaultClient()
 }
 
-type Error struct {
-	error string `json:"error"`
-	message string `json:"message"`
-	failures []map[string]interface{} `json:"failures,omitempty"`
-}
-
-func (e *Error) Error() string {
-	return e.message
-}
-
-type Filter struct {
-	Filters [][]interface{}
-}
+// defaultClient returns DefaultClient, first applying the package level
+// settings to a copy of it if they changed since they were last applied.
+func defaultClient() *Client {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
 
-func (f *Filter) Add(field, operator, value string) *Filter {
-	f.Filters = append(f.Filters, []interface{}{field, operator, value})
-	return f
-}
+	settings := [3]string{ACCOUNTS_SERVICE_API_URL, AUTHORIZATION_HEADER, ACCOUNTS_SERVICE_TIMEOUT}
 
-func (f *Filter) String() (str string) {
-	for _, filter := range f.Filters {
-		str += fmt.Sprintf("filter[%s][%s]=%s",filter...)
+	if settings == defaultSettings {
+		return DefaultClient
 	}
-	return
-}
 
-func GetCustomer(customerId int) (customer *Customer, err error) {
-	var getCustomerRequest *http.Request
+	defaultSettings = settings
 
-	getCustomerRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/customers/%d", ACCOUNTS_SERVICE_API_URL, customerId), nil)
-	
-	if err != nil {
-		return
+	baseUrl := ACCOUNTS_SERVICE_API_URL
+	if baseUrl == "" {
+		baseUrl = "http://localhost:8000"
 	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getCustomerRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+	timeout, err := time.ParseDuration(ACCOUNTS_SERVICE_TIMEOUT)
+	if err != nil || timeout <= 0 {
+		timeout = 30 * time.Second
 	}
 
-	var resp *http.Response
+	client := *DefaultClient
+	WithBaseUrl(baseUrl)(&client)
+	WithAuthorization(AUTHORIZATION_HEADER)(&client)
 
-	resp, err = client.Do(getCustomerRequest)
+	httpClient := *client.httpClient
+	httpClient.Timeout = timeout
+	client.httpClient = &httpClient
+	client.timeout = timeout
 
-	if err != nil {
-		return
-	}
+	DefaultClient = &client
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+	return DefaultClient
+}
 
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
+// Client talks to a single accounts service environment. Create one with
+// NewClient; a Client is safe for concurrent use.
+type Client struct {
+	baseUrl     string
+	auth        AuthProvider
+	userAgent   string
+	timeout     time.Duration
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	breaker     *CircuitBreaker
+	cache       *Cache
+	logger      Logger
+	middlewares []Middleware
+
+	skipFilterValidation bool
+}
 
-		err = &accountsError
+// Option configures a Client.
+type Option func(*Client)
 
-		return
+// WithBaseUrl sets the accounts service base url, e.g. http://localhost:8000.
+func WithBaseUrl(baseUrl string) Option {
+	return func(c *Client) {
+		c.baseUrl = strings.TrimRight(baseUrl, "/")
 	}
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&customer)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
+// WithAuthorization sets a static value sent in the Authorization header. Use
+// WithAuthProvider for credentials that change over time.
+func WithAuthorization(authorization string) Option {
+	return func(c *Client) {
+		c.auth = nil
+		if authorization != "" {
+			c.auth = StaticAuth(authorization)
+		}
 	}
-
-	return
 }
 
-func GetCustomerOrders(customerId int) (orders []Order, err error) {
-	var getCustomerOrdersRequest *http.Request
-
-	getCustomerOrdersRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/customers/%d/orders", ACCOUNTS_SERVICE_API_URL, customerId), nil)
-	
-	if err != nil {
-		return
+// WithHttpClient sets the http.Client used to make requests.
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
 	}
+}
 
-	if AUTHORIZATION_HEADER != "" {
-		getCustomerOrdersRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+// WithTimeout sets the request timeout, 30 seconds by default. When combined
+// with WithHttpClient the given http.Client is copied rather than modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
 	}
+}
 
-	var resp *http.Response
-
-	resp, err = client.Do(getCustomerOrdersRequest)
-
-	if err != nil {
-		return
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
 	}
+}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
-
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
-
-		err = &accountsError
-
-		return
+// NewClient returns a Client configured with options. Without options it talks
+// to http://localhost:8000 with a 30 second timeout.
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		baseUrl: "http://localhost:8000",
+		logger:  nopLogger{},
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&orders)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
+	for _, option := range options {
+		option(c)
 	}
 
-	return
-}
-
-func GetCustomerPaymentOptions(customerId int) (paymentOptions []PaymentOption, err error) {
-	var getCustomerPaymentOptionsRequest *http.Request
-
-	getCustomerPaymentOptionsRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/customers/%d/payment_options", ACCOUNTS_SERVICE_API_URL, customerId), nil)
-	
-	if err != nil {
-		return
+	if c.logger == nil {
+		c.logger = nopLogger{}
 	}
 
-	if AUTHORIZATION_HEADER != "" {
-		getCustomerPaymentOptionsRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+	if c.httpClient == nil {
+		if c.timeout == 0 {
+			c.timeout = 30 * time.Second
+		}
+		c.httpClient = &http.Client{
+			Timeout: c.timeout,
+		}
+	} else if c.timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
 	}
 
-	var resp *http.Response
-
-	resp, err = client.Do(getCustomerPaymentOptionsRequest)
-
-	if err != nil {
-		return
+	if len(c.middlewares) > 0 {
+		c.Use(c.middlewares...)
 	}
 
-	defer resp.Body.Close()
+	return c
+}
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+// newRequest builds a request for url. A non nil body is sent json encoded.
+func (c *Client) newRequest(ctx context.Context, method, url string, body interface{}) (req *http.Request, err error) {
+	var reader io.Reader
+
+	if body != nil {
+		var data []byte
+		data, err = json.Marshal(body)
 
 		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
+			return
 		}
 
-		err = &accountsError
-
-		return
+		reader = bytes.NewReader(data)
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&paymentOptions)
+	req, err = http.NewRequestWithContext(ctx, method, url, reader)
 
 	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
 		return
 	}
 
-	return
-}
-
-func GetCustomerTransactions(customerId int) (transactions []Transaction, err error) {
-	var getCustomerTransactionsRequest *http.Request
+	req.Header.Set("Accept", "application/json")
 
-	getCustomerTransactionsRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/transactions?filter[customer_id][eq]=%d", ACCOUNTS_SERVICE_API_URL, customerId), nil)
-	
-	if err != nil {
-		return
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
-	if AUTHORIZATION_HEADER != "" {
-		getCustomerTransactionsRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	var resp *http.Response
-
-	resp, err = client.Do(getCustomerTransactionsRequest)
+	return
+}
 
-	if err != nil {
+// do authorizes and sends req, retrying idempotent requests according to the
+// client's RetryPolicy and failing fast while its CircuitBreaker is open. A 401
+// response is retried once after invalidating the credentials. When the
+// request fails because its context was canceled or its deadline passed, the
+// context error is returned as is so callers can check for context.Canceled
+// and context.DeadlineExceeded.
+func (c *Client) do(ctx context.Context, req *http.Request) (resp *http.Response, err error) {
+	if err = c.authorize(ctx, req); err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
-
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
-
-		err = &accountsError
+	resp, err = c.send(ctx, req)
 
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
 		return
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&transactions)
+	invalidator, ok := c.auth.(AuthInvalidator)
 
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
+	if !ok {
 		return
 	}
 
-	return
-}
+	discard(resp.Body)
+	invalidator.Invalidate()
 
-func GetSubscription(subscriptionId int) (subscription *Subscription, err error) {
-	var getSubscriptionRequest *http.Request
+	c.logger.Info("retrying accounts service request with refreshed credentials", "method", req.Method, "path", req.URL.Path)
 
-	getSubscriptionRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/subscriptions/%d", ACCOUNTS_SERVICE_API_URL, subscriptionId), nil)
-	
-	if err != nil {
-		return
+	if err = rewind(req); err != nil {
+		return nil, err
 	}
 
-	if AUTHORIZATION_HEADER != "" {
-		getSubscriptionRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+	if err = c.authorize(ctx, req); err != nil {
+		return nil, err
 	}
 
-	var resp *http.Response
-
-	resp, err = client.Do(getSubscriptionRequest)
+	return c.send(ctx, req)
+}
 
-	if err != nil {
-		return
+// authorize sets the Authorization header from the client's AuthProvider.
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	if c.auth == nil {
+		return nil
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
-
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
-
-		err = &accountsError
+	authorization, err := c.auth.Authorization(ctx)
 
-		return
+	if err != nil {
+		return err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&subscription)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
 	}
 
-	return
+	return nil
 }
 
-func GetSubscriptionByOrderPlan(orderId int, planSku string) (subscription *Subscription, err error) {
-	var getSubscriptionByOrderPlanRequest *http.Request
-
-	getSubscriptionByOrderPlanRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/subscriptions/?filter[order_id][eq]=%d&filter[plan_sku][eq]=%s", ACCOUNTS_SERVICE_API_URL, orderId, planSku), nil)
-	
-	if err != nil {
-		return
-	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getSubscriptionByOrderPlanRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+// send sends req, retrying it according to the client's RetryPolicy.
+func (c *Client) send(ctx context.Context, req *http.Request) (resp *http.Response, err error) {
+	attempts := 1
+	if c.retryPolicy.MaxAttempts > 1 && idempotent(req) {
+		attempts = c.retryPolicy.MaxAttempts
 	}
 
-	var resp *http.Response
-
-	resp, err = client.Do(getSubscriptionByOrderPlanRequest)
-
-	if err != nil {
-		return
-	}
+	for attempt := 1; ; attempt++ {
+		var generation int
+		if c.breaker != nil {
+			if generation, err = c.breaker.allow(); err != nil {
+				c.logger.Warn("accounts service circuit breaker open", "method", req.Method, "path", req.URL.Path)
+				return
+			}
+		}
 
-	defer resp.Body.Close()
+		start := time.Now()
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+		resp, err = c.httpClient.Do(req)
 
 		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
+			c.logger.Debug("accounts service request failed", "method", req.Method, "path", req.URL.Path, "latency", time.Since(start), "attempt", attempt, "error", err)
+		} else {
+			c.logger.Debug("accounts service request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "latency", time.Since(start), "attempt", attempt, "request_id", resp.Header.Get("X-Request-Id"))
 		}
 
-		err = &accountsError
+		if err != nil && ctx.Err() != nil {
+			if c.breaker != nil {
+				c.breaker.forget(generation)
+			}
+			return nil, ctx.Err()
+		}
 
-		return
-	}
+		if c.breaker != nil {
+			c.breaker.record(generation, resp, err)
+		}
 
-	var subscriptions []*Subscription
+		if attempt >= attempts || !c.retryPolicy.retryable(resp, err) {
+			return
+		}
 
-	err = json.NewDecoder(resp.Body).Decode(&subscriptions)
+		wait, ok := c.retryPolicy.backoff(attempt, resp)
 
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
-	}
-
-	subscription = subscriptions[0]
+		if !ok {
+			c.logger.Warn("not retrying accounts service request, Retry-After exceeds the maximum backoff", "method", req.Method, "path", req.URL.Path, "attempt", attempt, "wait", wait)
+			return
+		}
 
-	return
-}
+		c.logger.Warn("retrying accounts service request", "method", req.Method, "path", req.URL.Path, "attempt", attempt, "wait", wait)
 
-func GetOrderSubscriptions(orderId int) (subscriptions []Subscription, err error) {
-	var getOrderSubscriptionsRequest *http.Request
+		if resp != nil {
+			discard(resp.Body)
+		}
 
-	getOrderSubscriptionsRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/subscriptions?filter[order_id][eq]=%d", ACCOUNTS_SERVICE_API_URL, orderId), nil)
-	
-	if err != nil {
-		return
-	}
+		if err = sleep(ctx, wait); err != nil {
+			return nil, err
+		}
 
-	if AUTHORIZATION_HEADER != "" {
-		getOrderSubscriptionsRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+		if err = rewind(req); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	var resp *http.Response
+// get sends a GET request for path and decodes the response into v.
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	return c.request(ctx, http.MethodGet, path, nil, v)
+}
 
-	resp, err = client.Do(getOrderSubscriptionsRequest)
+// request sends a request for path with body json encoded and decodes the
+// response into v. Mutating requests carry an Idempotency-Key header, which
+// makes them safe to retry, and their errors are wrapped in IdempotencyError.
+// GET responses are served from and stored in the client's Cache, which
+// successful mutating requests invalidate.
+func (c *Client) request(ctx context.Context, method, path string, body, v interface{}) (err error) {
+	var ttl time.Duration
+	var cacheKey string
+	var generation uint64
 
-	if err != nil {
-		return
+	if c.cache != nil && method == http.MethodGet {
+		ttl = c.cache.ttl(path)
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
-
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
+	if ttl > 0 {
+		if cacheKey, err = c.cacheKey(ctx, path); err != nil {
+			return
 		}
 
-		err = &accountsError
+		var data []byte
+		var ok bool
 
-		return
+		if data, generation, ok = c.cache.get(cacheKey); ok {
+			return json.Unmarshal(data, v)
+		}
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&subscriptions)
+	var key string
 
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
+	if !idempotentMethod(method) {
+		key = idempotencyKey(ctx)
+
+		defer func() {
+			if err != nil {
+				err = &IdempotencyError{
+					Key: key,
+					Err: err,
+				}
+			}
+		}()
 	}
 
-	return
-}
+	var req *http.Request
 
-func GetPlan(brandSlug, sku string) (plan *Plan, err error) {
-	var getPlanRequest *http.Request
+	req, err = c.newRequest(ctx, method, c.baseUrl+path, body)
 
-	getPlanRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/brands/%s/plans/%s", ACCOUNTS_SERVICE_API_URL, brandSlug, sku), nil)
-	
 	if err != nil {
 		return
 	}
 
-	if AUTHORIZATION_HEADER != "" {
-		getPlanRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
 	}
 
 	var resp *http.Response
 
-	resp, err = client.Do(getPlanRequest)
+	resp, err = c.do(ctx, req)
 
 	if err != nil {
 		return
@@ -407,367 +398,298 @@ func GetPlan(brandSlug, sku string) (plan *Plan, err error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
+	var cached *bytes.Buffer
 
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+	if ttl > 0 && resp.StatusCode == http.StatusOK {
+		cached = &bytes.Buffer{}
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(resp.Body, cached), resp.Body}
+	}
 
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
+	err = decodeResponse(resp, v)
 
-		err = &accountsError
+	if decodeError, ok := err.(*DecodeError); ok {
+		c.logger.Error("unable to decode accounts service response", "method", req.Method, "path", req.URL.Path, "error", decodeError)
+	}
 
+	if err != nil || c.cache == nil {
 		return
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&plan)
-
-	if err != nil {
-		fmt.Println("GetPlan: Unable to json decode response body", err)
-		return
+	if cached != nil {
+		c.cache.set(cacheKey, path, cached.Bytes(), ttl, generation)
+	} else if !idempotentMethod(method) {
+		c.cache.invalidate(path)
 	}
 
 	return
 }
 
-func GetProduct(brandSlug, sku string) (product *Product, err error) {
-	var getProductRequest *http.Request
-
-	getProductRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/brands/%s/products/%s", ACCOUNTS_SERVICE_API_URL, brandSlug, sku), nil)
-	
-	if err != nil {
-		return
-	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getProductRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
+func (c *Client) GetCustomer(customerId int) (*Customer, error) {
+	return c.GetCustomerContext(context.Background(), customerId)
+}
 
-	var resp *http.Response
+func (c *Client) GetCustomerContext(ctx context.Context, customerId int) (customer *Customer, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/customers/%d", customerId), &customer)
 
-	resp, err = client.Do(getProductRequest)
+	return
+}
 
-	if err != nil {
-		return
-	}
+func (c *Client) GetCustomerOrders(customerId int) ([]Order, error) {
+	return c.GetCustomerOrdersContext(context.Background(), customerId)
+}
 
-	defer resp.Body.Close()
+func (c *Client) GetCustomerOrdersContext(ctx context.Context, customerId int) (orders []Order, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/customers/%d/orders", customerId), &orders)
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+	return
+}
 
-		if err != nil {
-			fmt.Println("GetProduct: Unable to json decode error response body", err)
-		}
+func (c *Client) GetCustomerPaymentOptions(customerId int) ([]PaymentOption, error) {
+	return c.GetCustomerPaymentOptionsContext(context.Background(), customerId)
+}
 
-		err = &accountsError
+func (c *Client) GetCustomerPaymentOptionsContext(ctx context.Context, customerId int) (paymentOptions []PaymentOption, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/customers/%d/payment_options", customerId), &paymentOptions)
 
-		return
-	}
+	return
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&product)
+func (c *Client) GetCustomerTransactions(customerId int) ([]Transaction, error) {
+	return c.GetCustomerTransactionsContext(context.Background(), customerId)
+}
 
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
-	}
+func (c *Client) GetCustomerTransactionsContext(ctx context.Context, customerId int) (transactions []Transaction, err error) {
+	err = c.get(ctx, withQuery("/v1/transactions", NewFilter().Eq("customer_id", customerId).Values()), &transactions)
 
 	return
 }
 
-func GetSubscriptionOrders(subscriptionId int) (orders []Order, err error) {
-	var getSubscriptionOrdersRequest *http.Request
+func (c *Client) GetSubscription(subscriptionId int) (*Subscription, error) {
+	return c.GetSubscriptionContext(context.Background(), subscriptionId)
+}
 
-	getSubscriptionOrdersRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/subscriptions/%d/orders", ACCOUNTS_SERVICE_API_URL, subscriptionId), nil)
-	
-	if err != nil {
-		return
-	}
+func (c *Client) GetSubscriptionContext(ctx context.Context, subscriptionId int) (subscription *Subscription, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/subscriptions/%d", subscriptionId), &subscription)
 
-	if AUTHORIZATION_HEADER != "" {
-		getSubscriptionOrdersRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
+	return
+}
 
-	var resp *http.Response
+func (c *Client) GetSubscriptionByOrderPlan(orderId int, planSku string) (*Subscription, error) {
+	return c.GetSubscriptionByOrderPlanContext(context.Background(), orderId, planSku)
+}
+
+// GetSubscriptionByOrderPlanContext returns the subscription started for
+// planSku by an order. It fails with ErrNotFound when there is none and with
+// an *AmbiguousError when there are several.
+func (c *Client) GetSubscriptionByOrderPlanContext(ctx context.Context, orderId int, planSku string) (subscription *Subscription, err error) {
+	var subscriptions []*Subscription
 
-	resp, err = client.Do(getSubscriptionOrdersRequest)
+	err = c.get(ctx, withQuery("/v1/subscriptions", NewFilter().Eq("order_id", orderId).Eq("plan_sku", planSku).Values()), &subscriptions)
 
 	if err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
-
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
+	switch len(subscriptions) {
+	case 0:
+		err = fmt.Errorf("%w: no subscription for order %d and plan %s", ErrNotFound, orderId, planSku)
+	case 1:
+		subscription = subscriptions[0]
+	default:
+		err = &AmbiguousError{
+			Subscriptions: subscriptions,
 		}
-
-		err = &accountsError
-
-		return
-	}
-
-	err = json.NewDecoder(resp.Body).Decode(&orders)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
 	}
 
 	return
 }
 
-func GetOrder(orderId int) (order *Order, err error) {
-	var getOrderRequest *http.Request
-
-	getOrderRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/orders/%d", ACCOUNTS_SERVICE_API_URL, orderId), nil)
-	
-	if err != nil {
-		return
-	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getOrderRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
+func (c *Client) GetOrderSubscriptions(orderId int) ([]Subscription, error) {
+	return c.GetOrderSubscriptionsContext(context.Background(), orderId)
+}
 
-	var resp *http.Response
+func (c *Client) GetOrderSubscriptionsContext(ctx context.Context, orderId int) (subscriptions []Subscription, err error) {
+	err = c.get(ctx, withQuery("/v1/subscriptions", NewFilter().Eq("order_id", orderId).Values()), &subscriptions)
 
-	resp, err = client.Do(getOrderRequest)
+	return
+}
 
-	if err != nil {
-		return
-	}
+func (c *Client) GetPlan(brandSlug, sku string) (*Plan, error) {
+	return c.GetPlanContext(context.Background(), brandSlug, sku)
+}
 
-	defer resp.Body.Close()
+func (c *Client) GetPlanContext(ctx context.Context, brandSlug, sku string) (plan *Plan, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/brands/%s/plans/%s", brandSlug, sku), &plan)
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+	return
+}
 
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
+func (c *Client) GetProduct(brandSlug, sku string) (*Product, error) {
+	return c.GetProductContext(context.Background(), brandSlug, sku)
+}
 
-		err = &accountsError
+func (c *Client) GetProductContext(ctx context.Context, brandSlug, sku string) (product *Product, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/brands/%s/products/%s", brandSlug, sku), &product)
 
-		return
-	}
+	return
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&order)
+func (c *Client) GetSubscriptionOrders(subscriptionId int) ([]Order, error) {
+	return c.GetSubscriptionOrdersContext(context.Background(), subscriptionId)
+}
 
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
-	}
+func (c *Client) GetSubscriptionOrdersContext(ctx context.Context, subscriptionId int) (orders []Order, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/subscriptions/%d/orders", subscriptionId), &orders)
 
 	return
 }
 
-// TODO
-func GetOrderPlans(orderId int) (plans []Plan, err error) {
-	return
+func (c *Client) GetOrder(orderId int) (*Order, error) {
+	return c.GetOrderContext(context.Background(), orderId)
 }
 
-// TODO
-func GetOrderProducts(orderId int) (product []Product, err error) {
+func (c *Client) GetOrderContext(ctx context.Context, orderId int) (order *Order, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/orders/%d", orderId), &order)
+
 	return
 }
 
-func GetPaymentOptions(filter string) (paymentOptions []PaymentOption, err error) {
-	var getPaymentOptionsRequest *http.Request
-
-	getPaymentOptionsRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/payment_options?%s", ACCOUNTS_SERVICE_API_URL, filter), nil)
-	
-	if err != nil {
-		return
-	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getPaymentOptionsRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
+func (c *Client) GetOrderPlans(orderId int) ([]OrderPlan, error) {
+	return c.GetOrderPlansContext(context.Background(), orderId)
+}
 
-	var resp *http.Response
+// GetOrderPlansContext returns the plans of an order with their ordered
+// quantities, sorted by sku.
+func (c *Client) GetOrderPlansContext(ctx context.Context, orderId int) (plans []OrderPlan, err error) {
+	var order *Order
 
-	resp, err = client.Do(getPaymentOptionsRequest)
+	order, err = c.GetOrderContext(ctx, orderId)
 
 	if err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
+	skus := sortedSkus(order.Plans)
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+	var results []*Plan
 
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
-
-		err = &accountsError
+	results, err = fetchConcurrently(ctx, skus, func(ctx context.Context, sku string) (*Plan, error) {
+		return c.GetPlanContext(ctx, order.BrandSlug, sku)
+	})
 
+	if err != nil {
 		return
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&paymentOptions)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
+	for i, sku := range skus {
+		plans = append(plans, OrderPlan{
+			Plan:     results[i],
+			Quantity: order.Plans[sku].Quantity,
+		})
 	}
 
 	return
 }
 
-func GetPaymentOption(paymentOptionId int) (paymentOption *PaymentOption, err error) {
-	var getPaymentOptionRequest *http.Request
-
-	getPaymentOptionRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/payment_options/%d", ACCOUNTS_SERVICE_API_URL, paymentOptionId), nil)
-	
-	if err != nil {
-		return
-	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getPaymentOptionRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
+func (c *Client) GetOrderProducts(orderId int) ([]OrderProduct, error) {
+	return c.GetOrderProductsContext(context.Background(), orderId)
+}
 
-	var resp *http.Response
+// GetOrderProductsContext returns the products of an order with their ordered
+// quantities, sorted by sku.
+func (c *Client) GetOrderProductsContext(ctx context.Context, orderId int) (products []OrderProduct, err error) {
+	var order *Order
 
-	resp, err = client.Do(getPaymentOptionRequest)
+	order, err = c.GetOrderContext(ctx, orderId)
 
 	if err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
+	skus := sortedSkus(order.Products)
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountsError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountsError)
+	var results []*Product
 
-		if err != nil {
-			fmt.Println("Unable to json decode error response body", err)
-		}
-
-		err = &accountsError
+	results, err = fetchConcurrently(ctx, skus, func(ctx context.Context, sku string) (*Product, error) {
+		return c.GetProductContext(ctx, order.BrandSlug, sku)
+	})
 
+	if err != nil {
 		return
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&paymentOption)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
+	for i, sku := range skus {
+		products = append(products, OrderProduct{
+			Product:  results[i],
+			Quantity: order.Products[sku].Quantity,
+		})
 	}
 
 	return
 }
 
-func GetOrdersAggregate(filter *Filter, group string, aggregate []string) (agg map[string]interface{}, err error) {
-	var getOrdersAggregateRequest *http.Request
-
-	var jsonAgg []byte
-	jsonAgg, err = json.Marshal(aggregate)
-
-	if err != nil {
-		return
-	}
-
-	getOrdersAggregateRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/transactions?%s&group=%s&aggregate=%s", ACCOUNTS_SERVICE_API_URL, filter, group, jsonAgg), nil)
-	
-	if err != nil {
-		return
-	}
-
-	if AUTHORIZATION_HEADER != "" {
-		getOrdersAggregateRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
-
-	var resp *http.Response
-
-	resp, err = client.Do(getOrdersAggregateRequest)
+func (c *Client) GetPaymentOptions(filter *Filter) ([]PaymentOption, error) {
+	return c.GetPaymentOptionsContext(context.Background(), filter)
+}
 
-	if err != nil {
+func (c *Client) GetPaymentOptionsContext(ctx context.Context, filter *Filter) (paymentOptions []PaymentOption, err error) {
+	if err = c.validate(PaymentOptionSchema, filter); err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
+	err = c.get(ctx, withQuery("/v1/payment_options", filter.Values()), &paymentOptions)
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountServiceError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountServiceError)
-
-		if err != nil {
-			fmt.Println("Unable to json decode response body", err)
-			return
-		}
-
-		err = &accountServiceError
-
-		return
-	}
+	return
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&agg)
+func (c *Client) GetPaymentOption(paymentOptionId int) (*PaymentOption, error) {
+	return c.GetPaymentOptionContext(context.Background(), paymentOptionId)
+}
 
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
-	}
+func (c *Client) GetPaymentOptionContext(ctx context.Context, paymentOptionId int) (paymentOption *PaymentOption, err error) {
+	err = c.get(ctx, fmt.Sprintf("/v1/payment_options/%d", paymentOptionId), &paymentOption)
 
 	return
 }
 
-func GetTransactions(filter string) (transactions []Transaction, err error) {
-	var getTransactionsRequest *http.Request
+func (c *Client) GetOrdersAggregate(filter *Filter, group string, aggregate []string) (map[string]interface{}, error) {
+	return c.GetOrdersAggregateContext(context.Background(), filter, group, aggregate)
+}
 
-	getTransactionsRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/transactions?%s", ACCOUNTS_SERVICE_API_URL, filter), nil)
-	
-	if err != nil {
+func (c *Client) GetOrdersAggregateContext(ctx context.Context, filter *Filter, group string, aggregate []string) (agg map[string]interface{}, err error) {
+	if err = c.validate(TransactionSchema, filter); err != nil {
 		return
 	}
 
-	if AUTHORIZATION_HEADER != "" {
-		getTransactionsRequest.Header.Add("Authorization", AUTHORIZATION_HEADER)
-	}
-
-	var resp *http.Response
-
-	resp, err = client.Do(getTransactionsRequest)
+	var jsonAgg []byte
+	jsonAgg, err = json.Marshal(aggregate)
 
 	if err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
+	values := filter.Values()
+	values.Set("group", group)
+	values.Set("aggregate", string(jsonAgg))
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		var accountServiceError Error
-		err = json.NewDecoder(resp.Body).Decode(&accountServiceError)
+	err = c.get(ctx, withQuery("/v1/transactions", values), &agg)
 
-		if err != nil {
-			fmt.Println("Unable to json decode response body", err)
-			return
-		}
+	return
+}
 
-		err = &accountServiceError
+func (c *Client) GetTransactions(filter *Filter) ([]Transaction, error) {
+	return c.GetTransactionsContext(context.Background(), filter)
+}
 
+func (c *Client) GetTransactionsContext(ctx context.Context, filter *Filter) (transactions []Transaction, err error) {
+	if err = c.validate(TransactionSchema, filter); err != nil {
 		return
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&transactions)
-
-	if err != nil {
-		fmt.Println("Unable to json decode response body", err)
-		return
-	}
+	err = c.get(ctx, withQuery("/v1/transactions", filter.Values()), &transactions)
 
 	return
 }
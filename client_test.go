@@ -0,0 +1,71 @@
+package accountsservice_test
+
+// stdlib
+import (
+	"errors"
+	"testing"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+	"github.com/the-control-group/go-accounts-service-client/accountsservicetest"
+)
+
+func TestGetSubscriptionByOrderPlan(t *testing.T) {
+	server := accountsservicetest.NewServer()
+	defer server.Close()
+
+	single := server.AddSubscription(accountsservice.Subscription{OrderId: 1, PlanSku: "gold & silver"})
+	server.AddSubscription(accountsservice.Subscription{OrderId: 2, PlanSku: "gold & silver"})
+	server.AddSubscription(accountsservice.Subscription{OrderId: 1, PlanSku: "gold"})
+	first := server.AddSubscription(accountsservice.Subscription{OrderId: 3, PlanSku: "basic plan"})
+	second := server.AddSubscription(accountsservice.Subscription{OrderId: 3, PlanSku: "basic plan"})
+
+	client := server.Client()
+
+	tests := []struct {
+		name    string
+		orderId int
+		planSku string
+		want    []int
+		err     error
+	}{
+		{"no match", 1, "silver", nil, accountsservice.ErrNotFound},
+		{"no match for a prefix", 1, "gold &", nil, accountsservice.ErrNotFound},
+		{"one match", 1, "gold & silver", []int{single.Id}, nil},
+		{"several matches", 3, "basic plan", []int{first.Id, second.Id}, accountsservice.ErrAmbiguous},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subscription, err := client.GetSubscriptionByOrderPlan(test.orderId, test.planSku)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			var ids []int
+
+			var ambiguousError *accountsservice.AmbiguousError
+			switch {
+			case errors.As(err, &ambiguousError):
+				for _, subscription := range ambiguousError.Subscriptions {
+					ids = append(ids, subscription.Id)
+				}
+			case subscription != nil:
+				ids = append(ids, subscription.Id)
+			}
+
+			if len(ids) != len(test.want) {
+				t.Fatalf("got subscriptions %v, want %v", ids, test.want)
+			}
+
+			for i := range ids {
+				if ids[i] != test.want[i] {
+					t.Fatalf("got subscriptions %v, want %v", ids, test.want)
+				}
+			}
+		})
+	}
+}
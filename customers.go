@@ -0,0 +1,70 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CustomerUpdate holds the customer fields to change. Nil fields are left
+// untouched.
+type CustomerUpdate struct {
+	FirstName *string       `json:"first_name,omitempty"`
+	LastName  *string       `json:"last_name,omitempty"`
+	Email     *string       `json:"email,omitempty"`
+	Phone     *string       `json:"phone,omitempty"`
+	Data      *CustomerData `json:"data,omitempty"`
+}
+
+// newCustomer holds the fields of a Customer that can be set on creation.
+type newCustomer struct {
+	FirstName string       `json:"first_name"`
+	LastName  string       `json:"last_name"`
+	Email     string       `json:"email"`
+	Phone     *string      `json:"phone,omitempty"`
+	BrandSlug string       `json:"brand_slug"`
+	Data      CustomerData `json:"data"`
+}
+
+func (c *Client) CreateCustomer(customer *Customer) (*Customer, error) {
+	return c.CreateCustomerContext(context.Background(), customer)
+}
+
+// CreateCustomerContext creates customer and returns it as stored by the
+// accounts service. Id, Created and Updated are ignored. Rejected fields are
+// listed in the Failures of the returned APIError.
+func (c *Client) CreateCustomerContext(ctx context.Context, customer *Customer) (created *Customer, err error) {
+	if customer == nil {
+		return nil, errors.New("accountsservice: customer is required")
+	}
+
+	err = c.request(ctx, http.MethodPost, "/v1/customers", &newCustomer{
+		FirstName: customer.FirstName,
+		LastName:  customer.LastName,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+		BrandSlug: customer.BrandSlug,
+		Data:      customer.Data,
+	}, &created)
+
+	return
+}
+
+func (c *Client) UpdateCustomer(customerId int, update *CustomerUpdate) (*Customer, error) {
+	return c.UpdateCustomerContext(context.Background(), customerId, update)
+}
+
+// UpdateCustomerContext changes the fields set in update and returns the
+// updated customer. Rejected fields are listed in the Failures of the returned
+// APIError.
+func (c *Client) UpdateCustomerContext(ctx context.Context, customerId int, update *CustomerUpdate) (customer *Customer, err error) {
+	if update == nil {
+		return nil, errors.New("accountsservice: customer update is required")
+	}
+
+	err = c.request(ctx, http.MethodPatch, fmt.Sprintf("/v1/customers/%d", customerId), update, &customer)
+
+	return
+}
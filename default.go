@@ -0,0 +1,281 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-currency"
+)
+
+// The package level functions below call the matching method on defaultClient().
+
+func GetCustomer(customerId int) (*Customer, error) {
+	return defaultClient().GetCustomer(customerId)
+}
+
+func GetCustomerOrders(customerId int) ([]Order, error) {
+	return defaultClient().GetCustomerOrders(customerId)
+}
+
+func GetCustomerPaymentOptions(customerId int) ([]PaymentOption, error) {
+	return defaultClient().GetCustomerPaymentOptions(customerId)
+}
+
+func GetCustomerTransactions(customerId int) ([]Transaction, error) {
+	return defaultClient().GetCustomerTransactions(customerId)
+}
+
+func GetSubscription(subscriptionId int) (*Subscription, error) {
+	return defaultClient().GetSubscription(subscriptionId)
+}
+
+func GetSubscriptionByOrderPlan(orderId int, planSku string) (*Subscription, error) {
+	return defaultClient().GetSubscriptionByOrderPlan(orderId, planSku)
+}
+
+func GetOrderSubscriptions(orderId int) ([]Subscription, error) {
+	return defaultClient().GetOrderSubscriptions(orderId)
+}
+
+func GetPlan(brandSlug, sku string) (*Plan, error) {
+	return defaultClient().GetPlan(brandSlug, sku)
+}
+
+func GetProduct(brandSlug, sku string) (*Product, error) {
+	return defaultClient().GetProduct(brandSlug, sku)
+}
+
+func GetSubscriptionOrders(subscriptionId int) ([]Order, error) {
+	return defaultClient().GetSubscriptionOrders(subscriptionId)
+}
+
+func GetOrder(orderId int) (*Order, error) {
+	return defaultClient().GetOrder(orderId)
+}
+
+func GetOrderPlans(orderId int) ([]OrderPlan, error) {
+	return defaultClient().GetOrderPlans(orderId)
+}
+
+func GetOrderProducts(orderId int) ([]OrderProduct, error) {
+	return defaultClient().GetOrderProducts(orderId)
+}
+
+func GetPaymentOptions(filter *Filter) ([]PaymentOption, error) {
+	return defaultClient().GetPaymentOptions(filter)
+}
+
+func GetPaymentOption(paymentOptionId int) (*PaymentOption, error) {
+	return defaultClient().GetPaymentOption(paymentOptionId)
+}
+
+func GetOrdersAggregate(filter *Filter, group string, aggregate []string) (map[string]interface{}, error) {
+	return defaultClient().GetOrdersAggregate(filter, group, aggregate)
+}
+
+func GetTransactions(filter *Filter) ([]Transaction, error) {
+	return defaultClient().GetTransactions(filter)
+}
+
+func GetCustomerContext(ctx context.Context, customerId int) (*Customer, error) {
+	return defaultClient().GetCustomerContext(ctx, customerId)
+}
+
+func GetCustomerOrdersContext(ctx context.Context, customerId int) ([]Order, error) {
+	return defaultClient().GetCustomerOrdersContext(ctx, customerId)
+}
+
+func GetCustomerPaymentOptionsContext(ctx context.Context, customerId int) ([]PaymentOption, error) {
+	return defaultClient().GetCustomerPaymentOptionsContext(ctx, customerId)
+}
+
+func GetCustomerTransactionsContext(ctx context.Context, customerId int) ([]Transaction, error) {
+	return defaultClient().GetCustomerTransactionsContext(ctx, customerId)
+}
+
+func GetSubscriptionContext(ctx context.Context, subscriptionId int) (*Subscription, error) {
+	return defaultClient().GetSubscriptionContext(ctx, subscriptionId)
+}
+
+func GetSubscriptionByOrderPlanContext(ctx context.Context, orderId int, planSku string) (*Subscription, error) {
+	return defaultClient().GetSubscriptionByOrderPlanContext(ctx, orderId, planSku)
+}
+
+func GetOrderSubscriptionsContext(ctx context.Context, orderId int) ([]Subscription, error) {
+	return defaultClient().GetOrderSubscriptionsContext(ctx, orderId)
+}
+
+func GetPlanContext(ctx context.Context, brandSlug, sku string) (*Plan, error) {
+	return defaultClient().GetPlanContext(ctx, brandSlug, sku)
+}
+
+func GetProductContext(ctx context.Context, brandSlug, sku string) (*Product, error) {
+	return defaultClient().GetProductContext(ctx, brandSlug, sku)
+}
+
+func GetSubscriptionOrdersContext(ctx context.Context, subscriptionId int) ([]Order, error) {
+	return defaultClient().GetSubscriptionOrdersContext(ctx, subscriptionId)
+}
+
+func GetOrderContext(ctx context.Context, orderId int) (*Order, error) {
+	return defaultClient().GetOrderContext(ctx, orderId)
+}
+
+func GetOrderPlansContext(ctx context.Context, orderId int) ([]OrderPlan, error) {
+	return defaultClient().GetOrderPlansContext(ctx, orderId)
+}
+
+func GetOrderProductsContext(ctx context.Context, orderId int) ([]OrderProduct, error) {
+	return defaultClient().GetOrderProductsContext(ctx, orderId)
+}
+
+func GetPaymentOptionsContext(ctx context.Context, filter *Filter) ([]PaymentOption, error) {
+	return defaultClient().GetPaymentOptionsContext(ctx, filter)
+}
+
+func GetPaymentOptionContext(ctx context.Context, paymentOptionId int) (*PaymentOption, error) {
+	return defaultClient().GetPaymentOptionContext(ctx, paymentOptionId)
+}
+
+func GetOrdersAggregateContext(ctx context.Context, filter *Filter, group string, aggregate []string) (map[string]interface{}, error) {
+	return defaultClient().GetOrdersAggregateContext(ctx, filter, group, aggregate)
+}
+
+func GetTransactionsContext(ctx context.Context, filter *Filter) ([]Transaction, error) {
+	return defaultClient().GetTransactionsContext(ctx, filter)
+}
+
+func ListCustomerOrders(ctx context.Context, customerId int, filter *Filter) *Iterator[Order] {
+	return defaultClient().ListCustomerOrders(ctx, customerId, filter)
+}
+
+func ListCustomerPaymentOptions(ctx context.Context, customerId int, filter *Filter) *Iterator[PaymentOption] {
+	return defaultClient().ListCustomerPaymentOptions(ctx, customerId, filter)
+}
+
+func ListCustomerTransactions(ctx context.Context, customerId int, filter *Filter) *Iterator[Transaction] {
+	return defaultClient().ListCustomerTransactions(ctx, customerId, filter)
+}
+
+func ListOrderSubscriptions(ctx context.Context, orderId int, filter *Filter) *Iterator[Subscription] {
+	return defaultClient().ListOrderSubscriptions(ctx, orderId, filter)
+}
+
+func ListSubscriptionOrders(ctx context.Context, subscriptionId int, filter *Filter) *Iterator[Order] {
+	return defaultClient().ListSubscriptionOrders(ctx, subscriptionId, filter)
+}
+
+func ListPaymentOptions(ctx context.Context, filter *Filter) *Iterator[PaymentOption] {
+	return defaultClient().ListPaymentOptions(ctx, filter)
+}
+
+func ListTransactions(ctx context.Context, filter *Filter) *Iterator[Transaction] {
+	return defaultClient().ListTransactions(ctx, filter)
+}
+
+func CreateCustomer(customer *Customer) (*Customer, error) {
+	return defaultClient().CreateCustomer(customer)
+}
+
+func CreateCustomerContext(ctx context.Context, customer *Customer) (*Customer, error) {
+	return defaultClient().CreateCustomerContext(ctx, customer)
+}
+
+func UpdateCustomer(customerId int, update *CustomerUpdate) (*Customer, error) {
+	return defaultClient().UpdateCustomer(customerId, update)
+}
+
+func UpdateCustomerContext(ctx context.Context, customerId int, update *CustomerUpdate) (*Customer, error) {
+	return defaultClient().UpdateCustomerContext(ctx, customerId, update)
+}
+
+func CancelSubscription(subscriptionId int, atPeriodEnd bool, reason string) (*Subscription, error) {
+	return defaultClient().CancelSubscription(subscriptionId, atPeriodEnd, reason)
+}
+
+func CancelSubscriptionContext(ctx context.Context, subscriptionId int, atPeriodEnd bool, reason string) (*Subscription, error) {
+	return defaultClient().CancelSubscriptionContext(ctx, subscriptionId, atPeriodEnd, reason)
+}
+
+func PauseSubscription(subscriptionId int) (*Subscription, error) {
+	return defaultClient().PauseSubscription(subscriptionId)
+}
+
+func PauseSubscriptionContext(ctx context.Context, subscriptionId int) (*Subscription, error) {
+	return defaultClient().PauseSubscriptionContext(ctx, subscriptionId)
+}
+
+func ResumeSubscription(subscriptionId int) (*Subscription, error) {
+	return defaultClient().ResumeSubscription(subscriptionId)
+}
+
+func ResumeSubscriptionContext(ctx context.Context, subscriptionId int) (*Subscription, error) {
+	return defaultClient().ResumeSubscriptionContext(ctx, subscriptionId)
+}
+
+func ChangeSubscriptionPlan(subscriptionId int, planSku string, prorate bool) (*Subscription, error) {
+	return defaultClient().ChangeSubscriptionPlan(subscriptionId, planSku, prorate)
+}
+
+func ChangeSubscriptionPlanContext(ctx context.Context, subscriptionId int, planSku string, prorate bool) (*Subscription, error) {
+	return defaultClient().ChangeSubscriptionPlanContext(ctx, subscriptionId, planSku, prorate)
+}
+
+func RefundTransaction(transaction *Transaction, amount *currency.Amount, reason string) (*Transaction, error) {
+	return defaultClient().RefundTransaction(transaction, amount, reason)
+}
+
+func RefundTransactionContext(ctx context.Context, transaction *Transaction, amount *currency.Amount, reason string) (*Transaction, error) {
+	return defaultClient().RefundTransactionContext(ctx, transaction, amount, reason)
+}
+
+func VoidTransaction(transactionId int, reason string) (*Transaction, error) {
+	return defaultClient().VoidTransaction(transactionId, reason)
+}
+
+func VoidTransactionContext(ctx context.Context, transactionId int, reason string) (*Transaction, error) {
+	return defaultClient().VoidTransactionContext(ctx, transactionId, reason)
+}
+
+func CreatePaymentOption(paymentOption *NewPaymentOption) (*PaymentOption, error) {
+	return defaultClient().CreatePaymentOption(paymentOption)
+}
+
+func CreatePaymentOptionContext(ctx context.Context, paymentOption *NewPaymentOption) (*PaymentOption, error) {
+	return defaultClient().CreatePaymentOptionContext(ctx, paymentOption)
+}
+
+func UpdatePaymentOptionExpiry(paymentOptionId int, expMonth, expYear string) (*PaymentOption, error) {
+	return defaultClient().UpdatePaymentOptionExpiry(paymentOptionId, expMonth, expYear)
+}
+
+func UpdatePaymentOptionExpiryContext(ctx context.Context, paymentOptionId int, expMonth, expYear string) (*PaymentOption, error) {
+	return defaultClient().UpdatePaymentOptionExpiryContext(ctx, paymentOptionId, expMonth, expYear)
+}
+
+func SetDefaultPaymentOption(paymentOptionId int) (*PaymentOption, error) {
+	return defaultClient().SetDefaultPaymentOption(paymentOptionId)
+}
+
+func SetDefaultPaymentOptionContext(ctx context.Context, paymentOptionId int) (*PaymentOption, error) {
+	return defaultClient().SetDefaultPaymentOptionContext(ctx, paymentOptionId)
+}
+
+func DeactivatePaymentOption(paymentOptionId int) (*PaymentOption, error) {
+	return defaultClient().DeactivatePaymentOption(paymentOptionId)
+}
+
+func DeactivatePaymentOptionContext(ctx context.Context, paymentOptionId int) (*PaymentOption, error) {
+	return defaultClient().DeactivatePaymentOptionContext(ctx, paymentOptionId)
+}
+
+func CreateOrder(order *NewOrder) (*Checkout, error) {
+	return defaultClient().CreateOrder(order)
+}
+
+func CreateOrderContext(ctx context.Context, order *NewOrder) (*Checkout, error) {
+	return defaultClient().CreateOrderContext(ctx, order)
+}
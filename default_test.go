@@ -0,0 +1,49 @@
+package accountsservice
+
+// stdlib
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDefaultClientSettings(t *testing.T) {
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":4}`))
+	}))
+	defer server.Close()
+
+	baseUrl, header, client := ACCOUNTS_SERVICE_API_URL, AUTHORIZATION_HEADER, DefaultClient
+	defer func() {
+		ACCOUNTS_SERVICE_API_URL, AUTHORIZATION_HEADER, DefaultClient = baseUrl, header, client
+	}()
+
+	var requests int
+	Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return next.RoundTrip(req)
+		})
+	})
+
+	ACCOUNTS_SERVICE_API_URL = server.URL
+	AUTHORIZATION_HEADER = "Bearer set in main"
+
+	customer, err := GetCustomer(4)
+
+	if err != nil || customer.Id != 4 {
+		t.Fatalf("got %v, %v, want customer 4", customer, err)
+	}
+
+	if authorization != "Bearer set in main" {
+		t.Errorf("got Authorization %q, want Bearer set in main", authorization)
+	}
+
+	if requests != 1 {
+		t.Errorf("middleware added with Use saw %d requests, want 1", requests)
+	}
+}
@@ -0,0 +1,133 @@
+package accountsservice
+
+// stdlib
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors matched by APIError through errors.Is, e.g.
+// errors.Is(err, ErrNotFound).
+var (
+	ErrNotFound     = errors.New("accountsservice: not found")
+	ErrUnauthorized = errors.New("accountsservice: unauthorized")
+	ErrForbidden    = errors.New("accountsservice: forbidden")
+	ErrValidation   = errors.New("accountsservice: validation failed")
+	ErrAmbiguous    = errors.New("accountsservice: ambiguous result")
+)
+
+// APIError is returned when the accounts service responds with an error
+// status.
+type APIError struct {
+	// StatusCode is the http status of the response.
+	StatusCode int `json:"-"`
+	// Code is the machine readable error from the response body.
+	Code string `json:"error"`
+	// Message is the human readable message from the response body.
+	Message string `json:"message"`
+	// Failures lists the individual validation failures, if any.
+	Failures []map[string]interface{} `json:"failures,omitempty"`
+	// RequestId is the X-Request-Id response header.
+	RequestId string `json:"-"`
+	// Body is the raw response body.
+	Body []byte `json:"-"`
+}
+
+// Error is the previous name of APIError.
+//
+// Deprecated: use APIError.
+type Error = APIError
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Code
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("accountsservice: %d %s", e.StatusCode, message)
+}
+
+// Is makes errors.Is match the sentinel error for the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// AmbiguousError is returned when a lookup expected to find a single
+// subscription matched several. It matches ErrAmbiguous with errors.Is.
+type AmbiguousError struct {
+	Subscriptions []*Subscription
+}
+
+func (e *AmbiguousError) Error() string {
+	ids := make([]string, len(e.Subscriptions))
+	for i, subscription := range e.Subscriptions {
+		ids[i] = strconv.Itoa(subscription.Id)
+	}
+	return fmt.Sprintf("accountsservice: ambiguous result: subscriptions %s match", strings.Join(ids, ", "))
+}
+
+func (e *AmbiguousError) Is(target error) bool {
+	return target == ErrAmbiguous
+}
+
+// IsNotFound reports whether err is a 404 from the accounts service.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err is a 401 from the accounts service.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is a 403 from the accounts service.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsValidation reports whether err is a 400 or 422 from the accounts service,
+// or a filter rejected before it was sent.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// Failures returns the validation failures reported by the accounts service
+// when err is an APIError.
+func Failures(err error) []map[string]interface{} {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.Failures
+	}
+	return nil
+}
+
+// newAPIError builds an APIError from an error response. A body that isn't
+// json is kept in Body only.
+func newAPIError(resp *http.Response) *APIError {
+	apiError := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestId:  resp.Header.Get("X-Request-Id"),
+	}
+
+	apiError.Body, _ = readBody(resp.Body)
+
+	json.Unmarshal(apiError.Body, apiError)
+
+	return apiError
+}
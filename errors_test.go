@@ -0,0 +1,41 @@
+package accountsservice
+
+// stdlib
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	failures := []map[string]interface{}{{"field": "email", "message": "invalid"}}
+
+	tests := []struct {
+		err  *APIError
+		want error
+	}{
+		{&APIError{StatusCode: http.StatusNotFound}, ErrNotFound},
+		{&APIError{StatusCode: http.StatusNotFound, Failures: failures}, ErrNotFound},
+		{&APIError{StatusCode: http.StatusUnauthorized}, ErrUnauthorized},
+		{&APIError{StatusCode: http.StatusForbidden}, ErrForbidden},
+		{&APIError{StatusCode: http.StatusBadRequest}, ErrValidation},
+		{&APIError{StatusCode: http.StatusUnprocessableEntity, Failures: failures}, ErrValidation},
+		{&APIError{StatusCode: http.StatusConflict, Failures: failures}, nil},
+		{&APIError{StatusCode: http.StatusInternalServerError}, nil},
+	}
+
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrValidation, ErrAmbiguous}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d with %d failures", test.err.StatusCode, len(test.err.Failures)), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", test.err)
+
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == test.want) {
+					t.Errorf("errors.Is(err, %v) = %v", sentinel, got)
+				}
+			}
+		})
+	}
+}
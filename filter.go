@@ -0,0 +1,260 @@
+package accountsservice
+
+// stdlib
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-currency"
+)
+
+// Operator is a filter comparison understood by the accounts service.
+type Operator string
+
+const (
+	Eq   Operator = "eq"
+	Ne   Operator = "ne"
+	Gt   Operator = "gt"
+	Gte  Operator = "gte"
+	Lt   Operator = "lt"
+	Lte  Operator = "lte"
+	In   Operator = "in"
+	Nin  Operator = "nin"
+	Like Operator = "like"
+	Null Operator = "null"
+)
+
+// Filter builds the query of a list request:
+//
+//	filter := NewFilter().
+//		Eq("status", "active").
+//		Gte("created", since).
+//		In("brand_slug", "a", "b").
+//		Sort("-created").
+//		Limit(50)
+//
+// Each entry of Filters is a field, operator and value triple. Values may be
+// strings, numbers, bools, times, currency amounts or, for In and Nin, slices.
+type Filter struct {
+	Filters [][]interface{}
+
+	sort     []string
+	fields   []string
+	limit    int
+	offset   int
+	pageSize int
+}
+
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// Add adds a filter on field with a raw operator and value.
+func (f *Filter) Add(field, operator, value string) *Filter {
+	f.Filters = append(f.Filters, []interface{}{field, operator, value})
+	return f
+}
+
+// Where adds a filter on field.
+func (f *Filter) Where(field string, operator Operator, value interface{}) *Filter {
+	f.Filters = append(f.Filters, []interface{}{field, operator, value})
+	return f
+}
+
+func (f *Filter) Eq(field string, value interface{}) *Filter {
+	return f.Where(field, Eq, value)
+}
+
+func (f *Filter) Ne(field string, value interface{}) *Filter {
+	return f.Where(field, Ne, value)
+}
+
+func (f *Filter) Gt(field string, value interface{}) *Filter {
+	return f.Where(field, Gt, value)
+}
+
+func (f *Filter) Gte(field string, value interface{}) *Filter {
+	return f.Where(field, Gte, value)
+}
+
+func (f *Filter) Lt(field string, value interface{}) *Filter {
+	return f.Where(field, Lt, value)
+}
+
+func (f *Filter) Lte(field string, value interface{}) *Filter {
+	return f.Where(field, Lte, value)
+}
+
+// In matches field against any of values. A single slice argument is expanded.
+func (f *Filter) In(field string, values ...interface{}) *Filter {
+	return f.Where(field, In, listValue(values))
+}
+
+// Nin matches field against none of values. A single slice argument is
+// expanded.
+func (f *Filter) Nin(field string, values ...interface{}) *Filter {
+	return f.Where(field, Nin, listValue(values))
+}
+
+// Like matches field against a pattern using % as wildcard.
+func (f *Filter) Like(field, pattern string) *Filter {
+	return f.Where(field, Like, pattern)
+}
+
+// IsNull matches field being null, or not null when null is false.
+func (f *Filter) IsNull(field string, null bool) *Filter {
+	return f.Where(field, Null, null)
+}
+
+// Sort orders results by fields. Prefix a field with - to sort descending.
+func (f *Filter) Sort(fields ...string) *Filter {
+	f.sort = append(f.sort, fields...)
+	return f
+}
+
+// Select limits the fields included in each result.
+func (f *Filter) Select(fields ...string) *Filter {
+	f.fields = append(f.fields, fields...)
+	return f
+}
+
+// Limit caps the number of results. List iterators stop after limit items.
+func (f *Filter) Limit(limit int) *Filter {
+	f.limit = limit
+	return f
+}
+
+// PageSize sets how many items list iterators fetch per request, by default
+// DefaultPageSize or the limit when it is smaller. Other calls ignore it.
+func (f *Filter) PageSize(pageSize int) *Filter {
+	f.pageSize = pageSize
+	return f
+}
+
+// Offset skips the first offset results.
+func (f *Filter) Offset(offset int) *Filter {
+	f.offset = offset
+	return f
+}
+
+// Values returns the filter as escaped query parameters. A nil Filter has no
+// values.
+func (f *Filter) Values() url.Values {
+	values := url.Values{}
+
+	if f == nil {
+		return values
+	}
+
+	for _, filter := range f.Filters {
+		if len(filter) != 3 {
+			continue
+		}
+		key := fmt.Sprintf("filter[%v][%v]", filter[0], filter[1])
+		values.Add(key, formatValue(filter[2]))
+	}
+
+	if len(f.sort) > 0 {
+		values.Set("sort", strings.Join(f.sort, ","))
+	}
+
+	if len(f.fields) > 0 {
+		values.Set("fields", strings.Join(f.fields, ","))
+	}
+
+	if f.limit > 0 {
+		values.Set("limit", strconv.Itoa(f.limit))
+	}
+
+	if f.offset > 0 {
+		values.Set("offset", strconv.Itoa(f.offset))
+	}
+
+	return values
+}
+
+// String returns the encoded query string.
+func (f *Filter) String() string {
+	return f.Values().Encode()
+}
+
+// withQuery appends the encoded values to path.
+func withQuery(path string, values url.Values) string {
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+// listValue expands a single slice argument of In and Nin.
+func listValue(values []interface{}) interface{} {
+	if len(values) == 1 {
+		kind := reflect.ValueOf(values[0]).Kind()
+		if kind == reflect.Slice || kind == reflect.Array {
+			return values[0]
+		}
+	}
+	return values
+}
+
+// formatValue formats a filter value as the accounts service expects it.
+// Slices are joined with commas.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return "null"
+		}
+		return v.Format(time.RFC3339)
+	case currency.Amount:
+		return v.String()
+	case *currency.Amount:
+		if v == nil {
+			return "null"
+		}
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = formatValue(rv.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "null"
+		}
+		return formatValue(rv.Elem().Interface())
+	}
+
+	return fmt.Sprint(value)
+}
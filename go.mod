@@ -1,5 +1,5 @@
 module github.com/the-control-group/go-accounts-service-client
 
-go 1.13
+go 1.18
 
 require github.com/the-control-group/go-currency v0.0.0-20200402052624-c383c4a80f78
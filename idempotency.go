@@ -0,0 +1,74 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+type idempotencyKeyContextKey struct{}
+
+// contextIdempotencyKey is the key set on a context, which only the first
+// mutating request made with the context uses.
+type contextIdempotencyKey struct {
+	key  string
+	used int32
+}
+
+// ContextWithIdempotencyKey returns a context that makes the next mutating
+// call made with it send key as its Idempotency-Key header. Without it every
+// mutating call generates a new key, so reuse the key from IdempotencyKey(err)
+// to retry a failed call without risking a double charge. Later calls made
+// with the context generate their own keys, so derive a new context for each
+// retry.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, &contextIdempotencyKey{key: key})
+}
+
+// IdempotencyError wraps the error of a mutating request together with the
+// Idempotency-Key it was sent with. The wrapped error is still matched by
+// errors.Is and errors.As.
+type IdempotencyError struct {
+	Key string
+	Err error
+}
+
+func (e *IdempotencyError) Error() string {
+	return fmt.Sprintf("%v (idempotency key %s)", e.Err, e.Key)
+}
+
+func (e *IdempotencyError) Unwrap() error {
+	return e.Err
+}
+
+// IdempotencyKey returns the Idempotency-Key of the failed mutating request
+// behind err, or an empty string.
+func IdempotencyKey(err error) string {
+	var idempotencyError *IdempotencyError
+	if errors.As(err, &idempotencyError) {
+		return idempotencyError.Key
+	}
+	return ""
+}
+
+// idempotencyKey returns the key set on ctx unless a request already used it,
+// generating one otherwise.
+func idempotencyKey(ctx context.Context) string {
+	if value, ok := ctx.Value(idempotencyKeyContextKey{}).(*contextIdempotencyKey); ok && value.key != "" && atomic.CompareAndSwapInt32(&value.used, 0, 1) {
+		return value.key
+	}
+
+	return newIdempotencyKey()
+}
+
+// newIdempotencyKey returns a random version 4 uuid.
+func newIdempotencyKey() string {
+	uuid := make([]byte, 16)
+	rand.Read(uuid)
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
@@ -0,0 +1,43 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextWithIdempotencyKey(t *testing.T) {
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Method+" "+r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseUrl(server.URL))
+	ctx := ContextWithIdempotencyKey(context.Background(), "retried")
+
+	client.GetCustomerContext(ctx, 1)
+	client.CancelSubscriptionContext(ctx, 1, false, "")
+	client.PauseSubscriptionContext(ctx, 1)
+
+	if len(keys) != 3 {
+		t.Fatalf("got %d requests, want 3", len(keys))
+	}
+
+	if keys[0] != "GET " {
+		t.Errorf("GET sent %q, want no Idempotency-Key", keys[0])
+	}
+
+	if keys[1] != "POST retried" {
+		t.Errorf("first write sent %q, want the context's key", keys[1])
+	}
+
+	if keys[2] == "POST " || keys[2] == "POST retried" {
+		t.Errorf("second write sent %q, want a new key", keys[2])
+	}
+}
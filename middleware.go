@@ -0,0 +1,97 @@
+package accountsservice
+
+// stdlib
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Middleware wraps the transport of a Client, e.g. to add headers, propagate
+// traces or sign requests. Like any http.RoundTripper, a middleware must not
+// modify the request it is given; clone it first.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to a http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middlewares to the client. The first middleware is the
+// outermost one and sees every request first.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// Use adds middlewares to the client after it was created. It must be called
+// before the client is used concurrently.
+func (c *Client) Use(middlewares ...Middleware) {
+	httpClient := *c.httpClient
+	httpClient.Transport = chain(httpClient.Transport, middlewares)
+	c.httpClient = &httpClient
+}
+
+// Use adds middlewares to DefaultClient. It must be called before any of the
+// package level functions are.
+func Use(middlewares ...Middleware) {
+	defaultClient().Use(middlewares...)
+}
+
+// chain wraps transport in middlewares so that the first middleware runs first.
+func chain(transport http.RoundTripper, middlewares []Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+
+	return transport
+}
+
+// HeaderMiddleware sets header on every request, replacing existing values.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			for key, values := range header {
+				req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// UserAgentMiddleware sets the User-Agent header on every request.
+func UserAgentMiddleware(userAgent string) Middleware {
+	return HeaderMiddleware(http.Header{"User-Agent": {userAgent}})
+}
+
+// RequestIdMiddleware sets the X-Request-Id header on requests that don't have
+// one yet. Ids come from generate, or are random hex strings when it is nil.
+func RequestIdMiddleware(generate func() string) Middleware {
+	if generate == nil {
+		generate = newRequestId
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Request-Id") == "" {
+				req = req.Clone(req.Context())
+				req.Header.Set("X-Request-Id", generate())
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func newRequestId() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,127 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// NewOrder describes an order to place for a customer. Plans and Products map
+// skus to the quantity ordered.
+type NewOrder struct {
+	CustomerId      int                      `json:"customer_id"`
+	PaymentOptionId int                      `json:"payment_option_id"`
+	BrandSlug       string                   `json:"brand_slug,omitempty"`
+	Plans           map[string]OrderQuantity `json:"plans,omitempty"`
+	Products        map[string]OrderQuantity `json:"products,omitempty"`
+	// IdempotencyKey identifies the order so that sending it again doesn't
+	// charge the customer twice. It takes precedence over a key set with
+	// ContextWithIdempotencyKey.
+	IdempotencyKey string `json:"-"`
+}
+
+// Checkout is the result of placing an order: the order itself, the
+// transaction charging it and the subscriptions started by its plans.
+type Checkout struct {
+	Order         *Order         `json:"order"`
+	Transaction   *Transaction   `json:"transaction"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+func (c *Client) CreateOrder(order *NewOrder) (*Checkout, error) {
+	return c.CreateOrderContext(context.Background(), order)
+}
+
+// CreateOrderContext places order and charges its payment option.
+func (c *Client) CreateOrderContext(ctx context.Context, order *NewOrder) (checkout *Checkout, err error) {
+	if order == nil || len(order.Plans) == 0 && len(order.Products) == 0 {
+		return nil, errors.New("accountsservice: order needs at least one plan or product")
+	}
+
+	for sku, quantity := range order.Plans {
+		if quantity.Quantity <= 0 {
+			return nil, errors.New("accountsservice: invalid quantity for plan " + sku)
+		}
+	}
+
+	for sku, quantity := range order.Products {
+		if quantity.Quantity <= 0 {
+			return nil, errors.New("accountsservice: invalid quantity for product " + sku)
+		}
+	}
+
+	if order.IdempotencyKey != "" {
+		ctx = ContextWithIdempotencyKey(ctx, order.IdempotencyKey)
+	}
+
+	err = c.request(ctx, http.MethodPost, "/v1/orders", order, &checkout)
+
+	return
+}
+
+// orderItemConcurrency bounds how many plans or products are fetched at once
+// by GetOrderPlans and GetOrderProducts.
+const orderItemConcurrency = 4
+
+func sortedSkus(quantities map[string]OrderQuantity) []string {
+	skus := make([]string, 0, len(quantities))
+	for sku := range quantities {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	return skus
+}
+
+// fetchConcurrently calls fetch for every sku, at most orderItemConcurrency at
+// a time, and returns the results in the order of skus. The first error
+// cancels the remaining fetches.
+func fetchConcurrently[T any](ctx context.Context, skus []string, fetch func(ctx context.Context, sku string) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]T, len(skus))
+	semaphore := make(chan struct{}, orderItemConcurrency)
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var err error
+
+	for i, sku := range skus {
+		wg.Add(1)
+
+		go func(i int, sku string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+
+			result, fetchErr := fetch(ctx, sku)
+
+			if fetchErr != nil {
+				once.Do(func() {
+					err = fmt.Errorf("accountsservice: fetching %s: %w", sku, fetchErr)
+					cancel()
+				})
+				return
+			}
+
+			results[i] = result
+		}(i, sku)
+	}
+
+	wg.Wait()
+
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	return results, err
+}
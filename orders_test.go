@@ -0,0 +1,185 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tracking wraps fetch, recording the most calls running at once in peak.
+func tracking(peak *int32, fetch func(ctx context.Context, sku string) (string, error)) func(ctx context.Context, sku string) (string, error) {
+	var active int32
+
+	return func(ctx context.Context, sku string) (string, error) {
+		running := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+
+		for {
+			max := atomic.LoadInt32(peak)
+			if running <= max || atomic.CompareAndSwapInt32(peak, max, running) {
+				break
+			}
+		}
+
+		return fetch(ctx, sku)
+	}
+}
+
+func TestFetchConcurrently(t *testing.T) {
+	skus := make([]string, 20)
+	for i := range skus {
+		skus[i] = strconv.Itoa(i)
+	}
+
+	var peak int32
+
+	results, err := fetchConcurrently(context.Background(), skus, tracking(&peak, func(ctx context.Context, sku string) (string, error) {
+		// Later skus finish first.
+		i, _ := strconv.Atoi(sku)
+		time.Sleep(time.Duration(len(skus)-i) * time.Millisecond)
+		return "plan " + sku, nil
+	}))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, sku := range skus {
+		if results[i] != "plan "+sku {
+			t.Fatalf("result %d is %q, want plan %s", i, results[i], sku)
+		}
+	}
+
+	if peak > orderItemConcurrency || peak < 2 {
+		t.Errorf("ran %d fetches at once, want between 2 and %d", peak, orderItemConcurrency)
+	}
+}
+
+func TestFetchConcurrentlyCancels(t *testing.T) {
+	errMissing := errors.New("missing")
+	skus := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+
+	var started, canceled int32
+	var failed atomic.Value
+
+	start := time.Now()
+
+	_, err := fetchConcurrently(context.Background(), skus, func(ctx context.Context, sku string) (string, error) {
+		// The first fetch fails once the others had time to start.
+		if atomic.AddInt32(&started, 1) == 1 {
+			failed.Store(sku)
+			time.Sleep(10 * time.Millisecond)
+			return "", errMissing
+		}
+
+		select {
+		case <-ctx.Done():
+			atomic.AddInt32(&canceled, 1)
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+			return sku, nil
+		}
+	})
+
+	if !errors.Is(err, errMissing) || !strings.Contains(err.Error(), "fetching "+failed.Load().(string)) {
+		t.Fatalf("got %v, want the error of the first fetch", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v, want the other fetches canceled", elapsed)
+	}
+
+	if canceled != started-1 {
+		t.Errorf("%d of %d other fetches were canceled", canceled, started-1)
+	}
+}
+
+func TestGetOrderPlans(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing string
+		err     error
+	}{
+		{"fetches every plan", "", nil},
+		{"fails on a missing plan", "plan-3", ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var peak, active int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.URL.Path == "/v1/orders/1" {
+					plans := make([]string, 10)
+					for i := range plans {
+						plans[i] = fmt.Sprintf(`"plan-%d":{"quantity":%d}`, i, i+1)
+					}
+					fmt.Fprintf(w, `{"id":1,"brand_slug":"brand","plans":{%s}}`, strings.Join(plans, ","))
+					return
+				}
+
+				running := atomic.AddInt32(&active, 1)
+				defer atomic.AddInt32(&active, -1)
+				for {
+					max := atomic.LoadInt32(&peak)
+					if running <= max || atomic.CompareAndSwapInt32(&peak, max, running) {
+						break
+					}
+				}
+
+				sku := strings.TrimPrefix(r.URL.Path, "/v1/brands/brand/plans/")
+
+				if sku == test.missing {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"error":"not_found"}`))
+					return
+				}
+
+				select {
+				case <-time.After(10 * time.Millisecond):
+				case <-r.Context().Done():
+					return
+				}
+
+				fmt.Fprintf(w, `{"brand_slug":"brand","sku":%q}`, sku)
+			}))
+			defer server.Close()
+
+			plans, err := NewClient(WithBaseUrl(server.URL)).GetOrderPlans(1)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if peak := atomic.LoadInt32(&peak); peak > orderItemConcurrency {
+				t.Errorf("fetched %d plans at once, want at most %d", peak, orderItemConcurrency)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			if len(plans) != 10 {
+				t.Fatalf("got %d plans, want 10", len(plans))
+			}
+
+			// Plans are sorted by sku.
+			for i := range plans {
+				sku := fmt.Sprintf("plan-%d", i)
+				if plans[i].Plan.Sku != sku || plans[i].Quantity != i+1 {
+					t.Errorf("plan %d is %s x %d, want %s x %d", i, plans[i].Plan.Sku, plans[i].Quantity, sku, i+1)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items fetched per page when a list call's
+// filter sets no page size.
+const DefaultPageSize = 100
+
+// ErrPagingUnsupported is returned by Iterator.Err when a list endpoint
+// ignores the offset, answering with the previous page again, so the items
+// after the first page can't be fetched.
+var ErrPagingUnsupported = errors.New("accountsservice: list endpoint ignores paging")
+
+// PageFunc fetches up to limit items starting at offset.
+type PageFunc[T any] func(ctx context.Context, limit, offset int) ([]T, error)
+
+// Iterator lazily walks the pages of a list endpoint:
+//
+//	it := client.ListTransactions(ctx, NewFilter().Eq("status", "approved"))
+//	for it.Next() {
+//		transaction := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    PageFunc[T]
+	pageSize int
+	offset   int
+	max      int
+	fetched  int
+	page     []T
+	previous []T
+	index    int
+	value    T
+	last     bool
+	err      error
+}
+
+// NewIterator returns an Iterator that fetches pages of pageSize items with
+// fetch. A page shorter than pageSize is taken to be the last one. A page
+// longer than pageSize is taken to hold every item and ends the iteration too,
+// while one repeating the previous page fails it with ErrPagingUnsupported.
+func NewIterator[T any](ctx context.Context, pageSize int, fetch PageFunc[T]) *Iterator[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &Iterator[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: pageSize,
+	}
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false when there are no more items or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.last {
+			return false
+		}
+
+		limit := it.pageSize
+		if it.max > 0 && it.max-it.fetched < limit {
+			limit = it.max - it.fetched
+		}
+
+		it.page, it.err = it.fetch(it.ctx, limit, it.offset)
+
+		if it.err != nil {
+			it.page = nil
+			return false
+		}
+
+		if len(it.page) > 0 && reflect.DeepEqual(it.page, it.previous) {
+			it.page, it.err = nil, ErrPagingUnsupported
+			return false
+		}
+
+		it.index = 0
+		it.offset += len(it.page)
+		it.fetched += len(it.page)
+		it.previous = it.page
+		it.last = len(it.page) != limit
+
+		if it.max > 0 && it.fetched >= it.max {
+			it.page = it.page[:len(it.page)-(it.fetched-it.max)]
+			it.last = true
+		}
+	}
+
+	it.value = it.page[it.index]
+	it.index++
+
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// paginate returns an Iterator over the json array served at path, starting at
+// the filter's offset and stopping after its limit. A filter that doesn't
+// match schema fails the iterator before any request is sent.
+func paginate[T any](c *Client, ctx context.Context, path string, schema *Schema, filter *Filter) *Iterator[T] {
+	query := filter.Values()
+
+	var pageSize, offset, max int
+	if filter != nil {
+		pageSize, offset, max = filter.pageSize, filter.offset, filter.limit
+	}
+
+	if pageSize <= 0 && max > 0 && max < DefaultPageSize {
+		pageSize = max
+	}
+
+	it := NewIterator(ctx, pageSize, func(ctx context.Context, limit, offset int) (page []T, err error) {
+		values := url.Values{}
+		for key, value := range query {
+			values[key] = value
+		}
+		values.Set("limit", strconv.Itoa(limit))
+		values.Set("offset", strconv.Itoa(offset))
+
+		err = c.get(ctx, withQuery(path, values), &page)
+		return
+	})
+
+	it.offset = offset
+	it.max = max
+	it.err = c.validate(schema, filter)
+
+	return it
+}
+
+// scoped returns a copy of filter with an additional eq filter on field.
+func scoped(filter *Filter, field string, value interface{}) *Filter {
+	scoped := NewFilter()
+	if filter != nil {
+		*scoped = *filter
+		scoped.Filters = append([][]interface{}(nil), filter.Filters...)
+	}
+	return scoped.Eq(field, value)
+}
+
+func (c *Client) ListCustomerOrders(ctx context.Context, customerId int, filter *Filter) *Iterator[Order] {
+	return paginate[Order](c, ctx, fmt.Sprintf("/v1/customers/%d/orders", customerId), OrderSchema, filter)
+}
+
+func (c *Client) ListCustomerPaymentOptions(ctx context.Context, customerId int, filter *Filter) *Iterator[PaymentOption] {
+	return paginate[PaymentOption](c, ctx, fmt.Sprintf("/v1/customers/%d/payment_options", customerId), PaymentOptionSchema, filter)
+}
+
+func (c *Client) ListCustomerTransactions(ctx context.Context, customerId int, filter *Filter) *Iterator[Transaction] {
+	return paginate[Transaction](c, ctx, "/v1/transactions", TransactionSchema, scoped(filter, "customer_id", customerId))
+}
+
+func (c *Client) ListOrderSubscriptions(ctx context.Context, orderId int, filter *Filter) *Iterator[Subscription] {
+	return paginate[Subscription](c, ctx, "/v1/subscriptions", SubscriptionSchema, scoped(filter, "order_id", orderId))
+}
+
+func (c *Client) ListSubscriptionOrders(ctx context.Context, subscriptionId int, filter *Filter) *Iterator[Order] {
+	return paginate[Order](c, ctx, fmt.Sprintf("/v1/subscriptions/%d/orders", subscriptionId), OrderSchema, filter)
+}
+
+func (c *Client) ListPaymentOptions(ctx context.Context, filter *Filter) *Iterator[PaymentOption] {
+	return paginate[PaymentOption](c, ctx, "/v1/payment_options", PaymentOptionSchema, filter)
+}
+
+func (c *Client) ListTransactions(ctx context.Context, filter *Filter) *Iterator[Transaction] {
+	return paginate[Transaction](c, ctx, "/v1/transactions", TransactionSchema, filter)
+}
@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package accountsservice
+
+// stdlib
+import (
+	"iter"
+)
+
+// All returns the remaining items for use with range. Iteration stops after
+// yielding an error.
+//
+//	for transaction, err := range client.ListTransactions(ctx, filter).All() {
+//	}
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package accountsservice_test
+
+// stdlib
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-accounts-service-client"
+	"github.com/the-control-group/go-accounts-service-client/accountsservicetest"
+)
+
+// counting returns a middleware counting the requests it sees.
+func counting(requests *int32) accountsservice.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return accountsservice.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(requests, 1)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func collect(t *testing.T, it *accountsservice.Iterator[accountsservice.Order]) (ids []int) {
+	t.Helper()
+
+	for it.Next() {
+		ids = append(ids, it.Value().Id)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return
+}
+
+func TestListPages(t *testing.T) {
+	server := accountsservicetest.NewServer()
+	defer server.Close()
+
+	for i := 0; i < 250; i++ {
+		server.AddOrder(accountsservice.Order{CustomerId: 1})
+	}
+	server.AddOrder(accountsservice.Order{CustomerId: 2})
+
+	var requests int32
+	client := server.Client(accountsservice.WithMiddleware(counting(&requests)))
+
+	ids := collect(t, client.ListCustomerOrders(context.Background(), 1, nil))
+
+	if len(ids) != 250 {
+		t.Fatalf("got %d orders, want 250", len(ids))
+	}
+
+	for i, id := range ids {
+		if id != i+1 {
+			t.Fatalf("order %d has id %d, want %d", i, id, i+1)
+		}
+	}
+
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+}
+
+func TestListEndpointIgnoringPaging(t *testing.T) {
+	orders := func(n int) []accountsservice.Order {
+		orders := make([]accountsservice.Order, n)
+		for i := range orders {
+			orders[i].Id = i + 1
+		}
+		return orders
+	}
+
+	tests := []struct {
+		name     string
+		serve    func(limit, offset int) []accountsservice.Order
+		items    int
+		requests int32
+		err      error
+	}{
+		{
+			name:     "longer than a page",
+			serve:    func(limit, offset int) []accountsservice.Order { return orders(150) },
+			items:    150,
+			requests: 1,
+		},
+		{
+			name: "exactly a page",
+			serve: func(limit, offset int) []accountsservice.Order {
+				return orders(accountsservice.DefaultPageSize)[offset:]
+			},
+			items:    accountsservice.DefaultPageSize,
+			requests: 2,
+		},
+		{
+			name:     "same page at every offset",
+			serve:    func(limit, offset int) []accountsservice.Order { return orders(accountsservice.DefaultPageSize) },
+			items:    accountsservice.DefaultPageSize,
+			requests: 2,
+			err:      accountsservice.ErrPagingUnsupported,
+		},
+		{
+			name:     "limit without offset",
+			serve:    func(limit, offset int) []accountsservice.Order { return orders(250)[:limit] },
+			items:    accountsservice.DefaultPageSize,
+			requests: 2,
+			err:      accountsservice.ErrPagingUnsupported,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(test.serve(limit, offset))
+			}))
+			defer server.Close()
+
+			var requests int32
+			client := accountsservice.NewClient(
+				accountsservice.WithBaseUrl(server.URL),
+				accountsservice.WithMiddleware(counting(&requests)),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			it := client.ListCustomerOrders(ctx, 1, nil)
+
+			items := 0
+			for it.Next() {
+				items++
+			}
+
+			if err := it.Err(); !errors.Is(err, test.err) {
+				t.Errorf("got error %v, want %v", err, test.err)
+			}
+
+			if items != test.items {
+				t.Errorf("got %d orders, want %d", items, test.items)
+			}
+
+			if requests != test.requests {
+				t.Errorf("got %d requests, want %d", requests, test.requests)
+			}
+		})
+	}
+}
+
+func TestListLimitAndPageSize(t *testing.T) {
+	server := accountsservicetest.NewServer()
+	defer server.Close()
+
+	for i := 0; i < 250; i++ {
+		server.AddOrder(accountsservice.Order{CustomerId: 1})
+	}
+
+	tests := []struct {
+		name     string
+		filter   *accountsservice.Filter
+		first    int
+		items    int
+		requests int32
+	}{
+		{"limit below page size", accountsservice.NewFilter().Limit(10), 1, 10, 1},
+		{"limit above page size", accountsservice.NewFilter().Limit(150), 1, 150, 2},
+		{"limit with page size", accountsservice.NewFilter().Limit(150).PageSize(40), 1, 150, 4},
+		{"page size", accountsservice.NewFilter().PageSize(50), 1, 250, 6},
+		{"offset and limit", accountsservice.NewFilter().Offset(240).Limit(20), 241, 10, 1},
+		{"limit beyond results", accountsservice.NewFilter().Limit(1000), 1, 250, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int32
+			client := server.Client(accountsservice.WithMiddleware(counting(&requests)))
+
+			ids := collect(t, client.ListCustomerOrders(context.Background(), 1, test.filter))
+
+			if len(ids) != test.items {
+				t.Fatalf("got %d orders, want %d", len(ids), test.items)
+			}
+
+			if ids[0] != test.first {
+				t.Errorf("first order has id %d, want %d", ids[0], test.first)
+			}
+
+			if requests != test.requests {
+				t.Errorf("got %d requests, want %d", requests, test.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package accountsservice
+
+// stdlib
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError reports an invalid filter expression. Pos is the byte offset of
+// the offending token in Expr; the message reports it as a character column.
+type SyntaxError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	column := e.Pos + 1
+	if e.Pos <= len(e.Expr) {
+		column = utf8.RuneCountInString(e.Expr[:e.Pos]) + 1
+	}
+	return fmt.Sprintf("accountsservice: filter syntax error at column %d: %s", column, e.Msg)
+}
+
+// ParseFilter compiles a human written filter expression into a Filter, e.g.
+//
+//	status = "active" and created >= 2026-01-01 and brand_slug in ("a", "b")
+//
+// Conditions are joined with and. Supported comparisons are =, !=, <>, >, >=,
+// <, <=, in, not in, like, is null and is not null. Values are quoted
+// strings, numbers, true, false, null, dates (2006-01-02) and RFC 3339
+// times; unquoted words are taken as strings. Keywords are case insensitive.
+func ParseFilter(expr string) (filter *Filter, err error) {
+	p := &parser{
+		expr: expr,
+	}
+
+	if err = p.lex(); err != nil {
+		return
+	}
+
+	filter = NewFilter()
+
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty filter expression")
+	}
+
+	for {
+		if err = p.condition(filter); err != nil {
+			return nil, err
+		}
+
+		next := p.next()
+
+		switch {
+		case next.kind == tokenEOF:
+			return
+		case next.keyword("and"):
+			continue
+		case next.keyword("or"):
+			return nil, p.errorf(next, "or is not supported, only and")
+		default:
+			return nil, p.errorf(next, "expected and, found %s", next)
+		}
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenValue
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) keyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type parser struct {
+	expr   string
+	tokens []token
+	index  int
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Expr: p.expr,
+		Pos:  t.pos,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEOF {
+		p.index++
+	}
+	return t
+}
+
+// condition parses a single comparison and adds it to filter.
+func (p *parser) condition(filter *Filter) (err error) {
+	field := p.next()
+
+	if field.kind != tokenWord || isKeyword(field.text) {
+		return p.errorf(field, "expected field name, found %s", field)
+	}
+
+	op := p.next()
+
+	switch {
+	case op.kind == tokenOperator:
+		var value interface{}
+		if value, err = p.value(); err != nil {
+			return
+		}
+		return p.comparison(filter, field.text, op, value)
+	case op.keyword("in"):
+		var values []interface{}
+		if values, err = p.list(); err != nil {
+			return
+		}
+		filter.Where(field.text, In, values)
+	case op.keyword("not"):
+		if in := p.next(); !in.keyword("in") {
+			return p.errorf(in, "expected in after not, found %s", in)
+		}
+		var values []interface{}
+		if values, err = p.list(); err != nil {
+			return
+		}
+		filter.Where(field.text, Nin, values)
+	case op.keyword("like"):
+		pattern := p.next()
+		if pattern.kind != tokenString {
+			return p.errorf(pattern, "expected quoted pattern after like, found %s", pattern)
+		}
+		filter.Like(field.text, pattern.text)
+	case op.keyword("is"):
+		null := true
+		next := p.next()
+		if next.keyword("not") {
+			null = false
+			next = p.next()
+		}
+		if !next.keyword("null") {
+			return p.errorf(next, "expected null, found %s", next)
+		}
+		filter.IsNull(field.text, null)
+	default:
+		return p.errorf(op, "expected operator after %s, found %s", field.text, op)
+	}
+
+	return
+}
+
+func (p *parser) comparison(filter *Filter, field string, op token, value interface{}) error {
+	var operator Operator
+
+	switch op.text {
+	case "=", "==":
+		operator = Eq
+	case "!=", "<>":
+		operator = Ne
+	case ">":
+		operator = Gt
+	case ">=":
+		operator = Gte
+	case "<":
+		operator = Lt
+	case "<=":
+		operator = Lte
+	default:
+		return p.errorf(op, "unknown operator %s", op)
+	}
+
+	if value == nil {
+		switch operator {
+		case Eq:
+			filter.IsNull(field, true)
+		case Ne:
+			filter.IsNull(field, false)
+		default:
+			return p.errorf(op, "null can only be compared with = or !=")
+		}
+		return nil
+	}
+
+	filter.Where(field, operator, value)
+
+	return nil
+}
+
+// list parses a parenthesized, comma separated list of values.
+func (p *parser) list() (values []interface{}, err error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, p.errorf(open, "expected ( to start list, found %s", open)
+	}
+
+	for {
+		var value interface{}
+		if value, err = p.value(); err != nil {
+			return
+		}
+		values = append(values, value)
+
+		next := p.next()
+
+		switch next.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return
+		default:
+			return nil, p.errorf(next, "expected , or ) in list, found %s", next)
+		}
+	}
+}
+
+// value parses a literal. Null is returned as nil.
+func (p *parser) value() (value interface{}, err error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return t.text, nil
+	case tokenValue:
+		return p.literal(t)
+	case tokenWord:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if isKeyword(t.text) {
+			return nil, p.errorf(t, "expected value, found %s", t)
+		}
+		return t.text, nil
+	}
+
+	return nil, p.errorf(t, "expected value, found %s", t)
+}
+
+// literal converts a number, date or time token.
+func (p *parser) literal(t token) (interface{}, error) {
+	if i, err := strconv.Atoi(t.text); err == nil {
+		return i, nil
+	}
+
+	if f, err := strconv.ParseFloat(t.text, 64); err == nil {
+		return f, nil
+	}
+
+	if d, err := time.Parse("2006-01-02", t.text); err == nil {
+		return d, nil
+	}
+
+	if d, err := time.Parse(time.RFC3339, t.text); err == nil {
+		return d, nil
+	}
+
+	return nil, p.errorf(t, "invalid number, date or time %s", t)
+}
+
+func isKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "in", "not", "like", "is":
+		return true
+	}
+	return false
+}
+
+// lex splits the expression into tokens.
+func (p *parser) lex() error {
+	expr := p.expr
+	i := 0
+
+	for i < len(expr) {
+		c, width := utf8.DecodeRuneInString(expr[i:])
+		start := i
+
+		switch {
+		case unicode.IsSpace(c):
+			i += width
+			continue
+		case c == '(':
+			p.tokens = append(p.tokens, token{tokenLParen, "(", start})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{tokenRParen, ")", start})
+			i++
+		case c == ',':
+			p.tokens = append(p.tokens, token{tokenComma, ",", start})
+			i++
+		case c == '"' || c == '\'':
+			text, end, err := unquote(expr, i)
+			if err != nil {
+				return &SyntaxError{Expr: expr, Pos: start, Msg: err.Error()}
+			}
+			p.tokens = append(p.tokens, token{tokenString, text, start})
+			i = end
+		case strings.ContainsRune("=!<>", c):
+			i++
+			for i < len(expr) && strings.ContainsRune("=<>", rune(expr[i])) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{tokenOperator, expr[start:i], start})
+		case isDigit(c) || c == '-' && i+1 < len(expr) && isDigit(rune(expr[i+1])):
+			i++
+			for i < len(expr) && (isDigit(rune(expr[i])) || strings.ContainsRune("-+:.TZ", rune(expr[i]))) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{tokenValue, expr[start:i], start})
+		case c == '_' || unicode.IsLetter(c):
+			for i < len(expr) {
+				c, width = utf8.DecodeRuneInString(expr[i:])
+				if c != '_' && c != '.' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+					break
+				}
+				i += width
+			}
+			p.tokens = append(p.tokens, token{tokenWord, expr[start:i], start})
+		default:
+			return &SyntaxError{Expr: expr, Pos: start, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+
+	p.tokens = append(p.tokens, token{tokenEOF, "", len(expr)})
+
+	return nil
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+// unquote reads the quoted string starting at expr[start] and returns its
+// content and the offset just past the closing quote. A backslash escapes the
+// next character.
+func unquote(expr string, start int) (text string, end int, err error) {
+	quote := expr[start]
+	var b strings.Builder
+
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 < len(expr) {
+				i++
+				b.WriteByte(expr[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(expr[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string")
+}
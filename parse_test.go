@@ -0,0 +1,104 @@
+package accountsservice
+
+// stdlib
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`status = "active"`, `filter[status][eq]=active`},
+		{`status == active`, `filter[status][eq]=active`},
+		{`status != 'canceled'`, `filter[status][ne]=canceled`},
+		{`status <> canceled`, `filter[status][ne]=canceled`},
+		{`cycle > 1 and cycle <= 12`, `filter[cycle][gt]=1&filter[cycle][lte]=12`},
+		{`amount >= 9.99`, `filter[amount][gte]=9.99`},
+		{`amount < -5`, `filter[amount][lt]=-5`},
+		{`created >= 2026-01-01`, `filter[created][gte]=2026-01-01T00:00:00Z`},
+		{`created < 2026-01-01T12:30:00Z`, `filter[created][lt]=2026-01-01T12:30:00Z`},
+		{`brand_slug in ("a", "b")`, `filter[brand_slug][in]=a,b`},
+		{`id NOT IN (1, 2, 3)`, `filter[id][nin]=1,2,3`},
+		{`email like "%@example.com"`, `filter[email][like]=%@example.com`},
+		{`canceled is null`, `filter[canceled][null]=true`},
+		{`canceled IS NOT NULL`, `filter[canceled][null]=false`},
+		{`canceled = null`, `filter[canceled][null]=true`},
+		{`canceled != null`, `filter[canceled][null]=false`},
+		{`default = true`, `filter[default][eq]=true`},
+		{`data.source = "web"`, `filter[data.source][eq]=web`},
+		{`name = "say \"hi\""`, `filter[name][eq]=say "hi"`},
+		{`naïve = 1`, `filter[naïve][eq]=1`},
+		{`first_name = José`, `filter[first_name][eq]=José`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			filter, err := ParseFilter(test.expr)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, _ := url.QueryUnescape(filter.String())
+
+			if got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		pos     int
+		message string
+	}{
+		{``, 0, "column 1: empty filter expression"},
+		{`   `, 3, "column 4: empty filter expression"},
+		{`status`, 6, "column 7: expected operator after status"},
+		{`= 1`, 0, "column 1: expected field name"},
+		{`status = `, 9, "column 10: expected value"},
+		{`a = 1 or b = 2`, 6, "column 7: or is not supported"},
+		{`a = 1 b = 2`, 6, "column 7: expected and"},
+		{`a in 1`, 5, "column 6: expected ( to start list"},
+		{`a in (1 2)`, 8, "column 9: expected , or )"},
+		{`a not like "x"`, 6, "column 7: expected in after not"},
+		{`a like x`, 7, "column 8: expected quoted pattern"},
+		{`a is empty`, 5, "column 6: expected null"},
+		{`a > null`, 2, "column 3: null can only be compared"},
+		{`a = "open`, 4, "column 5: unterminated string"},
+		{`a = 12:30`, 4, "column 5: invalid number"},
+		{`a = 1 and b ~ 2`, 12, "column 13: unexpected character '~'"},
+		{`naïve = 1 and é ~ 2`, 18, "column 17: unexpected character '~'"},
+		{`and = 1`, 0, "column 1: expected field name"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			filter, err := ParseFilter(test.expr)
+
+			if filter != nil {
+				t.Errorf("got filter %v with error", filter)
+			}
+
+			var syntaxError *SyntaxError
+			if !errors.As(err, &syntaxError) {
+				t.Fatalf("got %v, want *SyntaxError", err)
+			}
+
+			if syntaxError.Pos != test.pos {
+				t.Errorf("got pos %d, want %d", syntaxError.Pos, test.pos)
+			}
+
+			if !strings.Contains(err.Error(), test.message) {
+				t.Errorf("got %q, want it to contain %q", err, test.message)
+			}
+		})
+	}
+}
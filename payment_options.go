@@ -0,0 +1,94 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// NewPaymentOption describes a payment option to create from a token issued by
+// the payment processor.
+type NewPaymentOption struct {
+	CustomerId       int    `json:"customer_id"`
+	BrandSlug        string `json:"brand_slug,omitempty"`
+	PaymentProcessor string `json:"payment_processor"`
+	Token            string `json:"token"`
+	// Default makes the new payment option the customer's default.
+	Default bool `json:"default,omitempty"`
+}
+
+func (c *Client) CreatePaymentOption(paymentOption *NewPaymentOption) (*PaymentOption, error) {
+	return c.CreatePaymentOptionContext(context.Background(), paymentOption)
+}
+
+// CreatePaymentOptionContext creates a payment option from a processor token
+// and returns it.
+func (c *Client) CreatePaymentOptionContext(ctx context.Context, paymentOption *NewPaymentOption) (created *PaymentOption, err error) {
+	if paymentOption == nil || paymentOption.Token == "" || paymentOption.PaymentProcessor == "" {
+		return nil, errors.New("accountsservice: payment processor and token are required")
+	}
+
+	err = c.request(ctx, http.MethodPost, "/v1/payment_options", paymentOption, &created)
+
+	return
+}
+
+func (c *Client) UpdatePaymentOptionExpiry(paymentOptionId int, expMonth, expYear string) (*PaymentOption, error) {
+	return c.UpdatePaymentOptionExpiryContext(context.Background(), paymentOptionId, expMonth, expYear)
+}
+
+// UpdatePaymentOptionExpiryContext changes the expiry of a card, e.g. to "09"
+// and "2029", and returns the updated payment option.
+func (c *Client) UpdatePaymentOptionExpiryContext(ctx context.Context, paymentOptionId int, expMonth, expYear string) (paymentOption *PaymentOption, err error) {
+	if month, convErr := strconv.Atoi(expMonth); convErr != nil || month < 1 || month > 12 {
+		return nil, fmt.Errorf("accountsservice: invalid expiry month %q", expMonth)
+	}
+
+	if _, convErr := strconv.Atoi(expYear); convErr != nil {
+		return nil, fmt.Errorf("accountsservice: invalid expiry year %q", expYear)
+	}
+
+	body := struct {
+		PaymentProcessorDetails PaymentOptonPaymentProcessorDetails `json:"payment_processor_details"`
+	}{
+		PaymentOptonPaymentProcessorDetails{
+			ExpMonth: &expMonth,
+			ExpYear:  &expYear,
+		},
+	}
+
+	err = c.request(ctx, http.MethodPatch, fmt.Sprintf("/v1/payment_options/%d", paymentOptionId), &body, &paymentOption)
+
+	return
+}
+
+func (c *Client) SetDefaultPaymentOption(paymentOptionId int) (*PaymentOption, error) {
+	return c.SetDefaultPaymentOptionContext(context.Background(), paymentOptionId)
+}
+
+// SetDefaultPaymentOptionContext makes a payment option its customer's
+// default, replacing the previous default, and returns it.
+func (c *Client) SetDefaultPaymentOptionContext(ctx context.Context, paymentOptionId int) (paymentOption *PaymentOption, err error) {
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/payment_options/%d/default", paymentOptionId), struct{}{}, &paymentOption)
+
+	return
+}
+
+func (c *Client) DeactivatePaymentOption(paymentOptionId int) (*PaymentOption, error) {
+	return c.DeactivatePaymentOptionContext(context.Background(), paymentOptionId)
+}
+
+// DeactivatePaymentOptionContext deactivates a payment option so it can't be
+// charged anymore and returns it.
+func (c *Client) DeactivatePaymentOptionContext(ctx context.Context, paymentOptionId int) (paymentOption *PaymentOption, err error) {
+	body := struct {
+		Status string `json:"status"`
+	}{"inactive"}
+
+	err = c.request(ctx, http.MethodPatch, fmt.Sprintf("/v1/payment_options/%d", paymentOptionId), &body, &paymentOption)
+
+	return
+}
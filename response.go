@@ -0,0 +1,110 @@
+package accountsservice
+
+// stdlib
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxBodySize caps how much of a response body is read.
+const maxBodySize = 10 << 20
+
+// maxSnippetSize caps how much of a response body is kept in a DecodeError.
+const maxSnippetSize = 512
+
+// DecodeError is returned when a successful response can't be decoded, e.g.
+// because a proxy answered with an html page.
+type DecodeError struct {
+	StatusCode  int
+	ContentType string
+	// Snippet is the start of the response body.
+	Snippet string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("accountsservice: unable to decode %d %s response: %v: %q", e.StatusCode, e.ContentType, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeResponse json decodes a 2xx response into v. Any other status is
+// returned as an APIError.
+func decodeResponse(resp *http.Response, v interface{}) (err error) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp)
+	}
+
+	var body []byte
+	body, err = readBody(resp.Body)
+
+	contentType := resp.Header.Get("Content-Type")
+
+	decodeError := &DecodeError{
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+		Snippet:     snippet(body),
+	}
+
+	if err != nil {
+		decodeError.Err = err
+		return decodeError
+	}
+
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return
+	}
+
+	if !isJson(contentType) {
+		decodeError.Err = fmt.Errorf("unexpected content type %q", contentType)
+		return decodeError
+	}
+
+	if err = json.Unmarshal(body, v); err != nil {
+		decodeError.Err = err
+		return decodeError
+	}
+
+	return
+}
+
+// readBody reads at most maxBodySize bytes from body.
+func readBody(body io.Reader) (data []byte, err error) {
+	data, err = ioutil.ReadAll(io.LimitReader(body, maxBodySize+1))
+
+	if err == nil && len(data) > maxBodySize {
+		err = fmt.Errorf("response body exceeds %d bytes", maxBodySize)
+	}
+
+	return
+}
+
+// isJson reports whether contentType is a json media type. A missing content
+// type is assumed to be json.
+func isJson(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func snippet(body []byte) string {
+	if len(body) > maxSnippetSize {
+		return string(body[:maxSnippetSize]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,162 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how idempotent requests, including mutating requests
+// with an Idempotency-Key, are retried after transient failures. The zero
+// value never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// BaseBackoff is the wait before the second attempt, doubled for every
+	// attempt after that.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the computed backoff. A Retry-After header asking for a
+	// longer wait ends the retries instead. Zero means no cap.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of each backoff that is
+	// randomized so that callers don't retry in lockstep.
+	Jitter float64
+	// Retryable decides whether a response or error is worth retrying.
+	// DefaultRetryable is used when nil.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts with 100ms to 2s of jittered
+// exponential backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseBackoff: 100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+	Jitter:      0.5,
+}
+
+// WithRetryPolicy retries idempotent requests according to policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// DefaultRetryable retries connection errors and 502, 503 and 504 responses.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func (p RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(resp, err)
+	}
+	return DefaultRetryable(resp, err)
+}
+
+// backoff returns how long to wait after the given attempt failed. A
+// Retry-After header on resp takes precedence over the computed backoff; ok is
+// false when it asks for longer than MaxBackoff.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) (wait time.Duration, ok bool) {
+	if resp != nil {
+		if wait, ok = retryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait, p.MaxBackoff <= 0 || wait <= p.MaxBackoff
+		}
+	}
+
+	wait = p.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		wait -= time.Duration(p.Jitter * rand.Float64() * float64(wait))
+	}
+
+	return wait, true
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// http date.
+func retryAfter(header string) (wait time.Duration, ok bool) {
+	if header == "" {
+		return
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		wait = time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return
+}
+
+// idempotent reports whether req may be sent more than once. Mutating requests
+// are when they carry an Idempotency-Key.
+func idempotent(req *http.Request) bool {
+	return idempotentMethod(req.Method) || req.Header.Get("Idempotency-Key") != ""
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewind resets the body of req so it can be sent again.
+func rewind(req *http.Request) (err error) {
+	if req.Body == nil || req.GetBody == nil {
+		return
+	}
+	req.Body, err = req.GetBody()
+	return
+}
+
+// discard drains and closes a response body so the connection can be reused.
+func discard(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, 4096))
+	body.Close()
+}
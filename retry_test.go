@@ -0,0 +1,146 @@
+package accountsservice
+
+// stdlib
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+
+	for _, test := range tests {
+		if wait, ok := policy.backoff(test.attempt, nil); !ok || wait != test.want {
+			t.Errorf("backoff(%d) = %v, %v, want %v, true", test.attempt, wait, ok, test.want)
+		}
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		Jitter:      0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		wait, _ := policy.backoff(3, nil)
+		if wait < 200*time.Millisecond || wait > 400*time.Millisecond {
+			t.Fatalf("jittered backoff %v outside [200ms, 400ms]", wait)
+		}
+	}
+}
+
+func TestBackoffRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBackoff time.Duration
+		header     string
+		want       time.Duration
+		ok         bool
+	}{
+		{"seconds", 5 * time.Second, "2", 2 * time.Second, true},
+		{"zero", 5 * time.Second, "0", 0, true},
+		{"beyond max backoff", 5 * time.Second, "86400", 86400 * time.Second, false},
+		{"no max backoff", 0, "86400", 86400 * time.Second, true},
+		{"past date", 5 * time.Second, "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+		{"invalid", 5 * time.Second, "soon", 100 * time.Millisecond, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := RetryPolicy{
+				BaseBackoff: 100 * time.Millisecond,
+				MaxBackoff:  test.maxBackoff,
+			}
+			resp := &http.Response{Header: http.Header{"Retry-After": {test.header}}}
+
+			wait, ok := policy.backoff(1, resp)
+
+			if wait != test.want || ok != test.ok {
+				t.Errorf("got %v, %v, want %v, %v", wait, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int32
+		retryAfter string
+		status     int
+		requests   int32
+	}{
+		{"recovers", 2, "", http.StatusOK, 3},
+		{"gives up", 5, "", http.StatusServiceUnavailable, 3},
+		{"honors retry after", 1, "0", http.StatusOK, 2},
+		{"stops on long retry after", 1, "86400", http.StatusServiceUnavailable, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if atomic.AddInt32(&requests, 1) <= test.failures {
+					if test.retryAfter != "" {
+						w.Header().Set("Retry-After", test.retryAfter)
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+					w.Write([]byte(`{"error":"unavailable"}`))
+					return
+				}
+				w.Write([]byte(`{"id":1}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(
+				WithBaseUrl(server.URL),
+				WithRetryPolicy(RetryPolicy{
+					MaxAttempts: 3,
+					BaseBackoff: time.Millisecond,
+					MaxBackoff:  10 * time.Millisecond,
+				}),
+			)
+
+			start := time.Now()
+			_, err := client.GetCustomer(1)
+
+			var apiError *APIError
+			switch {
+			case test.status == http.StatusOK && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case test.status != http.StatusOK && (!errors.As(err, &apiError) || apiError.StatusCode != test.status):
+				t.Fatalf("got error %v, want status %d", err, test.status)
+			}
+
+			if requests != test.requests {
+				t.Errorf("got %d requests, want %d", requests, test.requests)
+			}
+
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %v", elapsed)
+			}
+		})
+	}
+}
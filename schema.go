@@ -0,0 +1,388 @@
+package accountsservice
+
+// stdlib
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-currency"
+)
+
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldInt
+	FieldFloat
+	FieldBool
+	FieldTime
+	FieldAmount
+	// FieldObject is a map or struct field. Its sub fields can be filtered
+	// with dotted names, e.g. data.source, and are not checked.
+	FieldObject
+)
+
+func (t FieldType) String() string {
+	switch t {
+	case FieldString:
+		return "string"
+	case FieldInt:
+		return "int"
+	case FieldFloat:
+		return "float"
+	case FieldBool:
+		return "bool"
+	case FieldTime:
+		return "time"
+	case FieldAmount:
+		return "amount"
+	case FieldObject:
+		return "object"
+	}
+	return "unknown"
+}
+
+type SchemaField struct {
+	Type     FieldType
+	Nullable bool
+}
+
+// Schema lists the fields of a resource that can be filtered, sorted and
+// selected.
+type Schema struct {
+	Resource string
+	Fields   map[string]SchemaField
+}
+
+// Schemas of the resources served by the list endpoints, derived from the
+// json tags of the matching structs.
+var (
+	TransactionSchema   = NewSchema("transaction", Transaction{})
+	OrderSchema         = NewSchema("order", Order{})
+	SubscriptionSchema  = NewSchema("subscription", Subscription{})
+	PaymentOptionSchema = NewSchema("payment option", PaymentOption{})
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	amountType = reflect.TypeOf(currency.Amount{})
+)
+
+// NewSchema derives a schema from the json tags of the struct v.
+func NewSchema(resource string, v interface{}) *Schema {
+	schema := &Schema{
+		Resource: resource,
+		Fields:   map[string]SchemaField{},
+	}
+
+	t := reflect.TypeOf(v)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if name == "" || name == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		schemaField := SchemaField{}
+		typ := field.Type
+
+		if typ.Kind() == reflect.Ptr {
+			schemaField.Nullable = true
+			typ = typ.Elem()
+		}
+
+		switch {
+		case typ == timeType:
+			schemaField.Type = FieldTime
+		case typ == amountType:
+			schemaField.Type = FieldAmount
+		default:
+			switch typ.Kind() {
+			case reflect.String:
+				schemaField.Type = FieldString
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				schemaField.Type = FieldInt
+			case reflect.Float32, reflect.Float64:
+				schemaField.Type = FieldFloat
+			case reflect.Bool:
+				schemaField.Type = FieldBool
+			default:
+				schemaField.Type = FieldObject
+				schemaField.Nullable = true
+			}
+		}
+
+		schema.Fields[name] = schemaField
+	}
+
+	return schema
+}
+
+// FilterProblem describes one invalid part of a Filter.
+type FilterProblem struct {
+	Field    string
+	Operator Operator
+	Message  string
+}
+
+func (p FilterProblem) String() string {
+	if p.Operator != "" {
+		return fmt.Sprintf("%s %s: %s", p.Field, p.Operator, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// FilterError is returned when a Filter doesn't match the schema of the
+// resource it is sent for. It matches ErrValidation with errors.Is.
+type FilterError struct {
+	Resource string
+	Problems []FilterProblem
+}
+
+func (e *FilterError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = problem.String()
+	}
+	return fmt.Sprintf("accountsservice: invalid %s filter: %s", e.Resource, strings.Join(problems, "; "))
+}
+
+func (e *FilterError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// WithFilterValidation turns client side validation of filters against the
+// resource schemas on or off. It is on by default.
+func WithFilterValidation(enabled bool) Option {
+	return func(c *Client) {
+		c.skipFilterValidation = !enabled
+	}
+}
+
+func (c *Client) validate(schema *Schema, filter *Filter) error {
+	if c.skipFilterValidation {
+		return nil
+	}
+	return schema.Validate(filter)
+}
+
+// Validate checks the fields, operators and values of filter, as well as its
+// sort and selected fields. It returns a *FilterError listing every problem.
+func (s *Schema) Validate(filter *Filter) error {
+	if filter == nil {
+		return nil
+	}
+
+	var problems []FilterProblem
+
+	for _, f := range filter.Filters {
+		if len(f) != 3 {
+			problems = append(problems, FilterProblem{Field: fmt.Sprint(f...), Message: "expected field, operator and value"})
+			continue
+		}
+
+		field := fmt.Sprint(f[0])
+		operator := Operator(fmt.Sprint(f[1]))
+
+		if message := s.check(field, operator, f[2]); message != "" {
+			problems = append(problems, FilterProblem{Field: field, Operator: operator, Message: message})
+		}
+	}
+
+	for _, field := range filter.sort {
+		if _, ok := s.field(strings.TrimPrefix(field, "-")); !ok {
+			problems = append(problems, FilterProblem{Field: field, Message: "unknown sort field"})
+		}
+	}
+
+	for _, field := range filter.fields {
+		if _, ok := s.field(field); !ok {
+			problems = append(problems, FilterProblem{Field: field, Message: "unknown field"})
+		}
+	}
+
+	if len(problems) > 0 {
+		return &FilterError{
+			Resource: s.Resource,
+			Problems: problems,
+		}
+	}
+
+	return nil
+}
+
+// field looks up name, allowing dotted sub fields of object fields.
+func (s *Schema) field(name string) (field SchemaField, ok bool) {
+	if field, ok = s.Fields[name]; ok {
+		return
+	}
+
+	if i := strings.Index(name, "."); i > 0 {
+		if field, ok = s.Fields[name[:i]]; ok && field.Type == FieldObject {
+			return
+		}
+	}
+
+	return SchemaField{}, false
+}
+
+// check returns why operator and value can't be used on field, or an empty
+// string when they can.
+func (s *Schema) check(name string, operator Operator, value interface{}) string {
+	field, ok := s.field(name)
+
+	if !ok {
+		return fmt.Sprintf("unknown field%s", s.suggest(name))
+	}
+
+	if field.Type == FieldObject {
+		return ""
+	}
+
+	switch operator {
+	case Eq, Ne:
+		if value == nil || value == "null" {
+			if !field.Nullable {
+				return "field is not nullable"
+			}
+			return ""
+		}
+		return checkValue(field.Type, value)
+	case Gt, Gte, Lt, Lte:
+		if field.Type == FieldBool {
+			return "bool fields can't be ordered"
+		}
+		return checkValue(field.Type, value)
+	case In, Nin:
+		for _, item := range listItems(value) {
+			if message := checkValue(field.Type, item); message != "" {
+				return message
+			}
+		}
+		return ""
+	case Like:
+		if field.Type != FieldString {
+			return fmt.Sprintf("like requires a string field, %s is %s", name, field.Type)
+		}
+		return checkValue(FieldString, value)
+	case Null:
+		if !field.Nullable {
+			return "field is not nullable"
+		}
+		return checkValue(FieldBool, value)
+	}
+
+	return "unknown operator"
+}
+
+// suggest returns a hint for a misspelled field name.
+func (s *Schema) suggest(name string) string {
+	normalized := strings.ToLower(strings.Replace(name, "_", "", -1))
+
+	for field := range s.Fields {
+		if strings.Replace(field, "_", "", -1) == normalized {
+			return fmt.Sprintf(", did you mean %s?", field)
+		}
+	}
+
+	return ""
+}
+
+// listItems splits the value of an in or nin filter into its items.
+func listItems(value interface{}) []interface{} {
+	if str, ok := value.(string); ok {
+		items := []interface{}{}
+		for _, item := range strings.Split(str, ",") {
+			items = append(items, item)
+		}
+		return items
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return items
+	}
+
+	return []interface{}{value}
+}
+
+// checkValue returns why value doesn't fit typ, or an empty string when it
+// does. Strings, as passed to Filter.Add, are parsed.
+func checkValue(typ FieldType, value interface{}) string {
+	if str, ok := value.(string); ok {
+		var err error
+		switch typ {
+		case FieldInt:
+			_, err = strconv.ParseInt(str, 10, 64)
+		case FieldFloat, FieldAmount:
+			_, err = strconv.ParseFloat(str, 64)
+		case FieldBool:
+			_, err = strconv.ParseBool(str)
+		case FieldTime:
+			if _, err = time.Parse(time.RFC3339, str); err != nil {
+				_, err = time.Parse("2006-01-02", str)
+			}
+		}
+		if err != nil {
+			return fmt.Sprintf("%q is not a valid %s", str, typ)
+		}
+		return ""
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	valid := false
+
+	switch typ {
+	case FieldString:
+		valid = rv.Kind() == reflect.String
+	case FieldInt:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			valid = true
+		}
+	case FieldFloat:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			valid = true
+		}
+	case FieldAmount:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Float32, reflect.Float64:
+			valid = true
+		default:
+			valid = rv.IsValid() && rv.Type() == amountType
+		}
+	case FieldBool:
+		valid = rv.Kind() == reflect.Bool
+	case FieldTime:
+		valid = rv.IsValid() && rv.Type() == timeType
+	}
+
+	if !valid {
+		return fmt.Sprintf("%v (%T) is not a valid %s", value, value, typ)
+	}
+
+	return ""
+}
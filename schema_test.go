@@ -0,0 +1,116 @@
+package accountsservice
+
+// stdlib
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-currency"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   *Schema
+		filter   *Filter
+		problems []string
+	}{
+		{"nil filter", TransactionSchema, nil, nil},
+		{"valid", TransactionSchema, NewFilter().
+			Eq("status", "approved").
+			Gte("created", time.Now()).
+			Gt("amount", currency.Amount{Dollars: 5}).
+			In("order_id", 1, 2).
+			Like("brand_slug", "br%").
+			IsNull("failure_code", true).
+			Eq("payment_processor_details.bin", "411111").
+			Sort("-created").
+			Select("id", "amount"), nil},
+		{"raw string values", TransactionSchema, NewFilter().
+			Add("order_id", "eq", "12").
+			Add("created", "gte", "2026-01-01").
+			Add("amount", "lt", "9.99").
+			Add("order_id", "in", "1,2,3"), nil},
+		{"order fields from json tags", OrderSchema, NewFilter().Eq("subsription_id", 7).IsNull("subsription_id", false), nil},
+		{"subscription canceled", SubscriptionSchema, NewFilter().Eq("canceled", nil).Lt("cycle", 3), nil},
+		{"payment option default", PaymentOptionSchema, NewFilter().Eq("default", true), nil},
+		{"unknown field", TransactionSchema, NewFilter().Eq("colour", "red"), []string{"colour eq: unknown field"}},
+		{"misspelled field", OrderSchema, NewFilter().Eq("customerId", 7), []string{"did you mean customer_id?"}},
+		{"null on non nullable", TransactionSchema, NewFilter().Eq("status", nil), []string{"status eq: field is not nullable"}},
+		{"null operator on non nullable", TransactionSchema, NewFilter().IsNull("status", true), []string{"status null: field is not nullable"}},
+		{"invalid int", TransactionSchema, NewFilter().Eq("order_id", "twelve"), []string{`"twelve" is not a valid int`}},
+		{"invalid int type", TransactionSchema, NewFilter().Eq("order_id", 1.5), []string{"1.5 (float64) is not a valid int"}},
+		{"invalid time", TransactionSchema, NewFilter().Gte("created", "yesterday"), []string{`"yesterday" is not a valid time`}},
+		{"ordered bool", PaymentOptionSchema, NewFilter().Gt("default", true), []string{"bool fields can't be ordered"}},
+		{"like on int", TransactionSchema, NewFilter().Like("order_id", "1%"), []string{"like requires a string field"}},
+		{"invalid list item", TransactionSchema, NewFilter().In("order_id", 1, "x"), []string{`"x" is not a valid int`}},
+		{"unknown operator", TransactionSchema, NewFilter().Add("status", "regex", "a.*"), []string{"status regex: unknown operator"}},
+		{"unknown sort field", TransactionSchema, NewFilter().Sort("-colour"), []string{"-colour: unknown sort field"}},
+		{"unknown selected field", TransactionSchema, NewFilter().Select("colour"), []string{"colour: unknown field"}},
+		{"every problem", TransactionSchema, NewFilter().Eq("colour", "red").Eq("order_id", "x").Sort("size"), []string{"colour", "order_id", "size"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.schema.Validate(test.filter)
+
+			if test.problems == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var filterError *FilterError
+			if !errors.As(err, &filterError) {
+				t.Fatalf("got %v, want *FilterError", err)
+			}
+
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("error doesn't match ErrValidation")
+			}
+
+			if len(filterError.Problems) != len(test.problems) {
+				t.Fatalf("got problems %v, want %d", filterError.Problems, len(test.problems))
+			}
+
+			for i, problem := range test.problems {
+				if !strings.Contains(filterError.Problems[i].String(), problem) {
+					t.Errorf("problem %d is %q, want it to contain %q", i, filterError.Problems[i], problem)
+				}
+			}
+		})
+	}
+}
+
+func TestClientFilterValidation(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	filter := NewFilter().Eq("colour", "red")
+
+	_, err := NewClient(WithBaseUrl(server.URL)).GetTransactions(filter)
+
+	if !errors.Is(err, ErrValidation) || requests != 0 {
+		t.Fatalf("got %v after %d requests, want a validation error before any request", err, requests)
+	}
+
+	_, err = NewClient(WithBaseUrl(server.URL), WithFilterValidation(false)).GetTransactions(filter)
+
+	if err != nil || requests != 1 {
+		t.Fatalf("got %v after %d requests, want the filter sent unvalidated", err, requests)
+	}
+}
@@ -0,0 +1,92 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-currency"
+)
+
+// AccountsService is every operation of the accounts service api. *Client
+// implements it; depend on it instead of the client or the package level
+// functions to substitute a fake, e.g. accountsservicemock.Mock, in tests.
+type AccountsService interface {
+	GetCustomer(customerId int) (*Customer, error)
+	GetCustomerContext(ctx context.Context, customerId int) (*Customer, error)
+	GetCustomerOrders(customerId int) ([]Order, error)
+	GetCustomerOrdersContext(ctx context.Context, customerId int) ([]Order, error)
+	GetCustomerPaymentOptions(customerId int) ([]PaymentOption, error)
+	GetCustomerPaymentOptionsContext(ctx context.Context, customerId int) ([]PaymentOption, error)
+	GetCustomerTransactions(customerId int) ([]Transaction, error)
+	GetCustomerTransactionsContext(ctx context.Context, customerId int) ([]Transaction, error)
+	CreateCustomer(customer *Customer) (*Customer, error)
+	CreateCustomerContext(ctx context.Context, customer *Customer) (*Customer, error)
+	UpdateCustomer(customerId int, update *CustomerUpdate) (*Customer, error)
+	UpdateCustomerContext(ctx context.Context, customerId int, update *CustomerUpdate) (*Customer, error)
+
+	GetSubscription(subscriptionId int) (*Subscription, error)
+	GetSubscriptionContext(ctx context.Context, subscriptionId int) (*Subscription, error)
+	GetSubscriptionByOrderPlan(orderId int, planSku string) (*Subscription, error)
+	GetSubscriptionByOrderPlanContext(ctx context.Context, orderId int, planSku string) (*Subscription, error)
+	GetSubscriptionOrders(subscriptionId int) ([]Order, error)
+	GetSubscriptionOrdersContext(ctx context.Context, subscriptionId int) ([]Order, error)
+	CancelSubscription(subscriptionId int, atPeriodEnd bool, reason string) (*Subscription, error)
+	CancelSubscriptionContext(ctx context.Context, subscriptionId int, atPeriodEnd bool, reason string) (*Subscription, error)
+	PauseSubscription(subscriptionId int) (*Subscription, error)
+	PauseSubscriptionContext(ctx context.Context, subscriptionId int) (*Subscription, error)
+	ResumeSubscription(subscriptionId int) (*Subscription, error)
+	ResumeSubscriptionContext(ctx context.Context, subscriptionId int) (*Subscription, error)
+	ChangeSubscriptionPlan(subscriptionId int, planSku string, prorate bool) (*Subscription, error)
+	ChangeSubscriptionPlanContext(ctx context.Context, subscriptionId int, planSku string, prorate bool) (*Subscription, error)
+
+	GetPlan(brandSlug, sku string) (*Plan, error)
+	GetPlanContext(ctx context.Context, brandSlug, sku string) (*Plan, error)
+	GetProduct(brandSlug, sku string) (*Product, error)
+	GetProductContext(ctx context.Context, brandSlug, sku string) (*Product, error)
+
+	GetOrder(orderId int) (*Order, error)
+	GetOrderContext(ctx context.Context, orderId int) (*Order, error)
+	GetOrderSubscriptions(orderId int) ([]Subscription, error)
+	GetOrderSubscriptionsContext(ctx context.Context, orderId int) ([]Subscription, error)
+	GetOrderPlans(orderId int) ([]OrderPlan, error)
+	GetOrderPlansContext(ctx context.Context, orderId int) ([]OrderPlan, error)
+	GetOrderProducts(orderId int) ([]OrderProduct, error)
+	GetOrderProductsContext(ctx context.Context, orderId int) ([]OrderProduct, error)
+	GetOrdersAggregate(filter *Filter, group string, aggregate []string) (map[string]interface{}, error)
+	GetOrdersAggregateContext(ctx context.Context, filter *Filter, group string, aggregate []string) (map[string]interface{}, error)
+	CreateOrder(order *NewOrder) (*Checkout, error)
+	CreateOrderContext(ctx context.Context, order *NewOrder) (*Checkout, error)
+
+	GetPaymentOption(paymentOptionId int) (*PaymentOption, error)
+	GetPaymentOptionContext(ctx context.Context, paymentOptionId int) (*PaymentOption, error)
+	GetPaymentOptions(filter *Filter) ([]PaymentOption, error)
+	GetPaymentOptionsContext(ctx context.Context, filter *Filter) ([]PaymentOption, error)
+	CreatePaymentOption(paymentOption *NewPaymentOption) (*PaymentOption, error)
+	CreatePaymentOptionContext(ctx context.Context, paymentOption *NewPaymentOption) (*PaymentOption, error)
+	UpdatePaymentOptionExpiry(paymentOptionId int, expMonth, expYear string) (*PaymentOption, error)
+	UpdatePaymentOptionExpiryContext(ctx context.Context, paymentOptionId int, expMonth, expYear string) (*PaymentOption, error)
+	SetDefaultPaymentOption(paymentOptionId int) (*PaymentOption, error)
+	SetDefaultPaymentOptionContext(ctx context.Context, paymentOptionId int) (*PaymentOption, error)
+	DeactivatePaymentOption(paymentOptionId int) (*PaymentOption, error)
+	DeactivatePaymentOptionContext(ctx context.Context, paymentOptionId int) (*PaymentOption, error)
+
+	GetTransactions(filter *Filter) ([]Transaction, error)
+	GetTransactionsContext(ctx context.Context, filter *Filter) ([]Transaction, error)
+	RefundTransaction(transaction *Transaction, amount *currency.Amount, reason string) (*Transaction, error)
+	RefundTransactionContext(ctx context.Context, transaction *Transaction, amount *currency.Amount, reason string) (*Transaction, error)
+	VoidTransaction(transactionId int, reason string) (*Transaction, error)
+	VoidTransactionContext(ctx context.Context, transactionId int, reason string) (*Transaction, error)
+
+	ListCustomerOrders(ctx context.Context, customerId int, filter *Filter) *Iterator[Order]
+	ListCustomerPaymentOptions(ctx context.Context, customerId int, filter *Filter) *Iterator[PaymentOption]
+	ListCustomerTransactions(ctx context.Context, customerId int, filter *Filter) *Iterator[Transaction]
+	ListOrderSubscriptions(ctx context.Context, orderId int, filter *Filter) *Iterator[Subscription]
+	ListSubscriptionOrders(ctx context.Context, subscriptionId int, filter *Filter) *Iterator[Order]
+	ListPaymentOptions(ctx context.Context, filter *Filter) *Iterator[PaymentOption]
+	ListTransactions(ctx context.Context, filter *Filter) *Iterator[Transaction]
+}
+
+var _ AccountsService = (*Client)(nil)
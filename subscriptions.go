@@ -0,0 +1,73 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+func (c *Client) CancelSubscription(subscriptionId int, atPeriodEnd bool, reason string) (*Subscription, error) {
+	return c.CancelSubscriptionContext(context.Background(), subscriptionId, atPeriodEnd, reason)
+}
+
+// CancelSubscriptionContext cancels a subscription immediately, or at the end
+// of the current period when atPeriodEnd is true, and returns the updated
+// subscription.
+func (c *Client) CancelSubscriptionContext(ctx context.Context, subscriptionId int, atPeriodEnd bool, reason string) (subscription *Subscription, err error) {
+	body := struct {
+		AtPeriodEnd bool   `json:"at_period_end"`
+		Reason      string `json:"reason,omitempty"`
+	}{atPeriodEnd, reason}
+
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/subscriptions/%d/cancel", subscriptionId), &body, &subscription)
+
+	return
+}
+
+func (c *Client) PauseSubscription(subscriptionId int) (*Subscription, error) {
+	return c.PauseSubscriptionContext(context.Background(), subscriptionId)
+}
+
+// PauseSubscriptionContext stops billing a subscription until it is resumed
+// and returns the updated subscription.
+func (c *Client) PauseSubscriptionContext(ctx context.Context, subscriptionId int) (subscription *Subscription, err error) {
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/subscriptions/%d/pause", subscriptionId), struct{}{}, &subscription)
+
+	return
+}
+
+func (c *Client) ResumeSubscription(subscriptionId int) (*Subscription, error) {
+	return c.ResumeSubscriptionContext(context.Background(), subscriptionId)
+}
+
+// ResumeSubscriptionContext resumes a paused subscription and returns the
+// updated subscription.
+func (c *Client) ResumeSubscriptionContext(ctx context.Context, subscriptionId int) (subscription *Subscription, err error) {
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/subscriptions/%d/resume", subscriptionId), struct{}{}, &subscription)
+
+	return
+}
+
+func (c *Client) ChangeSubscriptionPlan(subscriptionId int, planSku string, prorate bool) (*Subscription, error) {
+	return c.ChangeSubscriptionPlanContext(context.Background(), subscriptionId, planSku, prorate)
+}
+
+// ChangeSubscriptionPlanContext moves a subscription to the plan with planSku
+// and returns the updated subscription. With prorate the unused part of the
+// current period is credited against the new plan.
+func (c *Client) ChangeSubscriptionPlanContext(ctx context.Context, subscriptionId int, planSku string, prorate bool) (subscription *Subscription, err error) {
+	if planSku == "" {
+		return nil, errors.New("accountsservice: plan sku is required")
+	}
+
+	body := struct {
+		PlanSku string `json:"plan_sku"`
+		Prorate bool   `json:"prorate"`
+	}{planSku, prorate}
+
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/subscriptions/%d/change_plan", subscriptionId), &body, &subscription)
+
+	return
+}
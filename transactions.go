@@ -0,0 +1,113 @@
+package accountsservice
+
+// stdlib
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// internal
+import (
+	"github.com/the-control-group/go-currency"
+)
+
+// ErrInvalidRefundAmount is returned without contacting the accounts service
+// when a refund amount isn't positive or exceeds the original transaction.
+var ErrInvalidRefundAmount = errors.New("accountsservice: invalid refund amount")
+
+// ProcessorError is returned together with the resulting transaction when the
+// payment processor declined a refund or void.
+type ProcessorError struct {
+	Transaction    *Transaction
+	FailureCode    string
+	FailureMessage string
+}
+
+func (e *ProcessorError) Error() string {
+	return fmt.Sprintf("accountsservice: transaction %d failed at %s: %s: %s", e.Transaction.Id, e.Transaction.PaymentProcessor, e.FailureCode, e.FailureMessage)
+}
+
+func (c *Client) RefundTransaction(transaction *Transaction, amount *currency.Amount, reason string) (*Transaction, error) {
+	return c.RefundTransactionContext(context.Background(), transaction, amount, reason)
+}
+
+// RefundTransactionContext refunds amount of transaction, or all of it when
+// amount is nil, and returns the refund transaction. When the processor
+// declines the refund the refund transaction is returned along with a
+// *ProcessorError.
+func (c *Client) RefundTransactionContext(ctx context.Context, transaction *Transaction, amount *currency.Amount, reason string) (refund *Transaction, err error) {
+	if transaction == nil {
+		return nil, errors.New("accountsservice: transaction is required")
+	}
+
+	if amount != nil {
+		if cents(amount) <= 0 {
+			return nil, fmt.Errorf("%w: %s is not positive", ErrInvalidRefundAmount, amount)
+		}
+		if cents(amount) > cents(&transaction.Amount) {
+			return nil, fmt.Errorf("%w: %s exceeds the transaction amount of %s", ErrInvalidRefundAmount, amount, &transaction.Amount)
+		}
+	}
+
+	body := struct {
+		Amount *currency.Amount `json:"amount,omitempty"`
+		Reason string           `json:"reason,omitempty"`
+	}{amount, reason}
+
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/transactions/%d/refund", transaction.Id), &body, &refund)
+
+	if err == nil {
+		err = processorError(refund)
+	}
+
+	return
+}
+
+func (c *Client) VoidTransaction(transactionId int, reason string) (*Transaction, error) {
+	return c.VoidTransactionContext(context.Background(), transactionId, reason)
+}
+
+// VoidTransactionContext voids a transaction that hasn't settled yet and
+// returns the resulting transaction. When the processor declines the void the
+// transaction is returned along with a *ProcessorError.
+func (c *Client) VoidTransactionContext(ctx context.Context, transactionId int, reason string) (transaction *Transaction, err error) {
+	body := struct {
+		Reason string `json:"reason,omitempty"`
+	}{reason}
+
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/v1/transactions/%d/void", transactionId), &body, &transaction)
+
+	if err == nil {
+		err = processorError(transaction)
+	}
+
+	return
+}
+
+// processorError returns a *ProcessorError when transaction carries a
+// processor failure.
+func processorError(transaction *Transaction) error {
+	if transaction == nil || transaction.FailureCode == nil && transaction.FailureMessage == nil {
+		return nil
+	}
+
+	processorError := &ProcessorError{
+		Transaction: transaction,
+	}
+
+	if transaction.FailureCode != nil {
+		processorError.FailureCode = *transaction.FailureCode
+	}
+
+	if transaction.FailureMessage != nil {
+		processorError.FailureMessage = *transaction.FailureMessage
+	}
+
+	return processorError
+}
+
+func cents(amount *currency.Amount) int {
+	return amount.Dollars*100 + amount.Cents
+}